@@ -3,11 +3,17 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 
+	"github.com/myungbeans/blueprince-mcp/runtime/guard"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/spoilers"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/storage/drive"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
 	"gopkg.in/yaml.v3"
 )
@@ -22,20 +28,80 @@ const (
 	GoogleDriveScreenshotFolderField = "google_drive_screenshot_folder"
 	GoogleDriveSecretsField          = "google_drive_secrets_dir"
 	RootField                        = "root"
+	LocalStoreDirField               = "local_store_dir"
 
 	// Environment variable names for Claude Desktop
 	ObsidianVaultPathEnv           = "OBSIDIAN_VAULT_PATH"
 	GoogleDriveScreenshotFolderEnv = "GOOGLE_DRIVE_SCREENSHOT_FOLDER"
 	GoogleDriveSecretsEnv          = "GOOGLE_DRIVE_SECRETS_DIR"
 	RootEnv                        = "ROOT"
+	LocalStoreDirEnv               = "LOCAL_STORE_DIR"
+	ImageMaxBytesEnv               = "IMAGE_MAX_BYTES"
+	OCRTesseractPathEnv            = "OCR_TESSERACT_PATH"
+	OCRLanguageEnv                 = "OCR_LANGUAGE"
+
+	// DefaultImageMaxBytes keeps a single image comfortably inside the tool result limits of common MCP clients
+	DefaultImageMaxBytes = 750_000
+
+	// Transports the server can listen on
+	TransportStdio = "stdio"
+	TransportSSE   = "sse"
+	TransportHTTP  = "http"
+
+	// DefaultHost and DefaultPort are used by the sse and http transports when server.host or server.port aren't set
+	DefaultHost = "localhost"
+	DefaultPort = 8001
 )
 
+// Transports lists every transport the server can listen on
+var Transports = []string{TransportStdio, TransportSSE, TransportHTTP}
+
 // ServerConfig holds the server-specific configurations.
 type ServerConfig struct {
+	// Transport is stdio (the default), sse, or http for streamable HTTP. The server's --transport flag overrides it.
+	Transport string `yaml:"transport,omitempty"`
+	// Host and Port are where the sse and http transports listen
 	Host string `yaml:"host"`
 	Port int    `yaml:"port"`
 }
 
+// Addr returns the host:port the sse and http transports listen on
+func (c ServerConfig) Addr() string {
+	host, port := c.Host, c.Port
+	if host == "" {
+		host = DefaultHost
+	}
+	if port <= 0 {
+		port = DefaultPort
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// ValidateTransport checks that transport is one of Transports. An empty transport means stdio.
+func ValidateTransport(transport string) error {
+	if transport != "" && !slices.Contains(Transports, transport) {
+		return fmt.Errorf("invalid transport '%s'. Must be one of: %v", transport, Transports)
+	}
+	return nil
+}
+
+// ImagesConfig holds settings for images returned to the MCP client.
+type ImagesConfig struct {
+	// MaxBytes is the budget for one base64 encoded image in a tool result
+	MaxBytes int `yaml:"max_bytes"`
+	// Regions adds or overrides named screen regions, as fractions of the screenshot size
+	Regions map[string]utils.Region `yaml:"regions,omitempty"`
+}
+
+// OCRConfig holds settings for the optional ocr_screenshot tool.
+// The tool is only offered when the tesseract binary can be found.
+type OCRConfig struct {
+	// TesseractPath is the tesseract binary to run. Defaults to looking up "tesseract" on the PATH.
+	TesseractPath string `yaml:"tesseract_path,omitempty"`
+	// Language is the tesseract language pack(s) to use, e.g. "eng" or "eng+fra". Defaults to eng.
+	Language string `yaml:"language,omitempty"`
+}
+
 // Config holds all application configurations.
 type Config struct {
 	Server             ServerConfig `yaml:"server"`
@@ -43,6 +109,41 @@ type Config struct {
 	GoogleDriveFolder  string       `yaml:"google_drive_screenshot_folder"`
 	GoogleDriveSecrets string       `yaml:"google_drive_secrets_dir"`
 	Root               string       `yaml:"root"`
+	// LocalStoreDir is a plain folder (e.g. a network share) used as the storage backend when Google Drive isn't configured
+	LocalStoreDir string       `yaml:"local_store_dir,omitempty"`
+	Images        ImagesConfig `yaml:"images,omitempty"`
+	OCR           OCRConfig    `yaml:"ocr,omitempty"`
+	// Spoilers is the spoiler level, with per-category overrides. set_spoiler_level saves changes to it in the vault's meta dir.
+	Spoilers spoilers.Policy `yaml:"spoilers,omitempty"`
+	// Guard adds to or changes the rules checking note content written through the tools
+	Guard guard.Config `yaml:"guard,omitempty"`
+}
+
+// Regions returns every named screen region: the built in regions and presets, overridden by images.regions
+func (c *Config) Regions() map[string]utils.Region {
+	regions := make(map[string]utils.Region)
+	for _, defaults := range []map[string]utils.Region{utils.NamedRegions, utils.PresetRegions, c.Images.Regions} {
+		for name, region := range defaults {
+			regions[name] = region
+		}
+	}
+	return regions
+}
+
+// ImageMaxBytes returns the configured image budget, or DefaultImageMaxBytes if none is set
+func (c *Config) ImageMaxBytes() int {
+	if c.Images.MaxBytes <= 0 {
+		return DefaultImageMaxBytes
+	}
+	return c.Images.MaxBytes
+}
+
+// SecretsDir returns google_drive_secrets_dir, or ~/.blueprince_mcp where the setup utility puts it, if it isn't set
+func (c *Config) SecretsDir() (string, error) {
+	if c.GoogleDriveSecrets != "" {
+		return utils.ResolveAndCleanPath(c.GoogleDriveSecrets)
+	}
+	return utils.ResolveAndCleanPath(filepath.Join("~", drive.CONFIG_DIR))
 }
 
 // LoadConfig reads the configuration from the given YAML file path and validates it.
@@ -70,6 +171,24 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("config error for obsidian_vault_path: %w", err)
 	}
 
+	for name, region := range cfg.Images.Regions {
+		if err := region.Validate(); err != nil {
+			return nil, fmt.Errorf("config error for images.regions.%s: %w", name, err)
+		}
+	}
+
+	if err := cfg.Spoilers.Validate(); err != nil {
+		return nil, fmt.Errorf("config error for spoilers: %w", err)
+	}
+
+	if err := cfg.Guard.Validate(); err != nil {
+		return nil, fmt.Errorf("config error for guard: %w", err)
+	}
+
+	if err := ValidateTransport(cfg.Server.Transport); err != nil {
+		return nil, fmt.Errorf("config error for server.transport: %w", err)
+	}
+
 	// Validate required subdirectories
 	if err := validateBaseVaultStructure(cfg.ObsidianVaultPath); err != nil {
 		return nil, fmt.Errorf("config error in vault '%s': %w", cfg.ObsidianVaultPath, err)
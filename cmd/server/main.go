@@ -2,12 +2,20 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/myungbeans/blueprince-mcp/cmd/config"
 	"github.com/myungbeans/blueprince-mcp/runtime"
+	"github.com/myungbeans/blueprince-mcp/runtime/auth"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
 	"github.com/myungbeans/blueprince-mcp/runtime/storage/drive"
+	"github.com/myungbeans/blueprince-mcp/runtime/storage/local"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
 
 	"go.uber.org/zap"
 
@@ -21,10 +29,19 @@ const (
 	envRoot               = "ROOT"
 	envGoogleDriveFolder  = "GOOGLE_DRIVE_SCREENSHOT_FOLDER"
 	envGoogleDriveSecrets = "GOOGLE_DRIVE_SECRETS_DIR"
+	envLocalStoreDir      = "LOCAL_STORE_DIR"
+	envImageMaxBytes      = "IMAGE_MAX_BYTES"
+	envOCRTesseractPath   = "OCR_TESSERACT_PATH"
+	envOCRLanguage        = "OCR_LANGUAGE"
 	loggerKey             = "logger"
 )
 
 func main() {
+	transport := flag.String("transport", "", "Transport to serve MCP over: stdio, sse or http (streamable HTTP). Defaults to server.transport in the config, or stdio")
+	host := flag.String("host", "", "Host the sse and http transports listen on. Defaults to server.host in the config, or "+config.DefaultHost)
+	port := flag.Int("port", 0, fmt.Sprintf("Port the sse and http transports listen on. Defaults to server.port in the config, or %d", config.DefaultPort))
+	flag.Parse()
+
 	// Initialize logger early
 	// TODO: using NewDevelopment for more human-friendly output during dev
 	logger, err := zap.NewDevelopment()
@@ -44,6 +61,17 @@ func main() {
 			Root:               os.Getenv(envRoot),
 			GoogleDriveFolder:  os.Getenv(envGoogleDriveFolder),
 			GoogleDriveSecrets: os.Getenv(envGoogleDriveSecrets),
+			LocalStoreDir:      os.Getenv(envLocalStoreDir),
+			OCR: config.OCRConfig{
+				TesseractPath: os.Getenv(envOCRTesseractPath),
+				Language:      os.Getenv(envOCRLanguage),
+			},
+		}
+		if maxBytes := os.Getenv(envImageMaxBytes); maxBytes != "" {
+			cfg.Images.MaxBytes, err = strconv.Atoi(maxBytes)
+			if err != nil {
+				logger.Fatal("Invalid "+envImageMaxBytes, zap.Error(err))
+			}
 		}
 	} else {
 		cfg, err = config.LoadConfig(defaultConfigFilePath)
@@ -52,8 +80,10 @@ func main() {
 		}
 	}
 
-	var store *drive.GoogleDrive
-	if cfg.GoogleDriveSecrets != "" {
+	// store stays nil when no backend is configured so tools can report it instead of dereferencing an empty client
+	var store storage.Store
+	switch {
+	case cfg.GoogleDriveSecrets != "":
 		svc, err := drive.GetSvc(ctx, cfg.GoogleDriveSecrets, cfg.Root)
 		if err != nil {
 			logger.Fatal("Failed to get Google Drive client", zap.Error(err))
@@ -65,16 +95,33 @@ func main() {
 			logger.Fatal("Failed to load Google Drive config", zap.Error(err))
 		}
 
-		store = drive.NewStore(ctx, svc, vaultPath, cfg.GoogleDriveSecrets, driveConfig.FolderID)
+		store = drive.NewStore(ctx, svc, cfg.ObsidianVaultPath, cfg.GoogleDriveSecrets, driveConfig.FolderID)
+
+	case cfg.LocalStoreDir != "":
+		root, err := utils.ResolveAndCleanPath(cfg.LocalStoreDir)
+		if err != nil {
+			logger.Fatal("Failed to resolve local store dir", zap.Error(err))
+		}
+		if err := utils.ValidateDir(root); err != nil {
+			logger.Fatal("Invalid local store dir", zap.Error(err))
+		}
+		store = local.NewStore(root, cfg.ObsidianVaultPath)
 	}
 
+	rtime := runtime.NewHandler(cfg, store)
+
 	// Create a new MCP server
 	s := server.NewMCPServer(
 		"Blue Prince Architect Notes - SPOILER-FREE Note Taking",
 		server_version,
+		// Clients can subscribe to note resources to hear when they change on disk
+		server.WithResourceCapabilities(true, false),
+		// Tool calls over HTTP need a token with the tool's scope
+		server.WithToolHandlerMiddleware(auth.ToolMiddleware(ctx)),
+		// Subscriptions end with the session that made them
+		server.WithHooks(rtime.Hooks()),
 	)
 
-	rtime := runtime.NewHandler(cfg, store)
 	err = rtime.RegisterResources(ctx, s)
 	if err != nil {
 		logger.Fatal("Failed to register resources", zap.Error(err))
@@ -82,9 +129,38 @@ func main() {
 
 	rtime.RegisterTools(ctx, s)
 
-	// Start the stdio server
-	logger.Info("Initializing server...")
-	if err := server.ServeStdio(s); err != nil {
+	if err := rtime.RegisterPrompts(ctx, s); err != nil {
+		logger.Fatal("Failed to register prompts", zap.Error(err))
+	}
+
+	// Flags override the config
+	if *transport != "" {
+		cfg.Server.Transport = *transport
+	}
+	if *host != "" {
+		cfg.Server.Host = *host
+	}
+	if *port != 0 {
+		cfg.Server.Port = *port
+	}
+	if err := config.ValidateTransport(cfg.Server.Transport); err != nil {
+		logger.Fatal("Invalid transport", zap.Error(err))
+	}
+
+	// Serve until SIGINT or SIGTERM
+	logger.Info("Initializing server...", zap.String("transport", cfg.Server.Transport))
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	switch cfg.Server.Transport {
+	case config.TransportSSE:
+		err = rtime.ServeSSE(ctx, s, cfg.Server.Addr())
+	case config.TransportHTTP:
+		err = rtime.ServeStreamableHTTP(ctx, s, cfg.Server.Addr())
+	default:
+		err = rtime.ServeStdio(ctx, s)
+	}
+	if err != nil {
 		logger.Fatal("Server error", zap.Error(err))
 	}
+	logger.Info("Server stopped")
 }
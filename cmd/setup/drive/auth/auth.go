@@ -100,8 +100,8 @@ func (g *Authenticator) TestPermissions(folderName string) error {
 	}
 
 	// Test basic access by listing files
-	query := fmt.Sprintf("name='%s' and mimeType='application/vnd.google-apps.folder' and trashed=false", folderName)
-	if _, err := gd.Client.Files.List().Q(query).Do(); err != nil {
+	query := gdrive.NewQuery().Name(folderName).MimeType(gdrive.FOLDER_MIME_TYPE).Trashed(false)
+	if _, err := gd.Client.Files.List().Q(query.String()).Do(); err != nil {
 		return fmt.Errorf("unable to search for folder: %w", err)
 	}
 
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the vault to Google Drive",
+		Long:  `Uploads the vault's notes/ and meta/ dirs as a new snapshot in the vault_backups/ subfolder of the configured Google Drive folder using the backup_vault tool.`,
+		Example: `  # Back up the vault
+  blueprince-tools backup`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := NewClient(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+
+			resp, err := client.CallTool("backup_vault", map[string]interface{}{})
+			if err != nil {
+				return fmt.Errorf("failed to call backup_vault: %w", err)
+			}
+
+			return client.PrettyPrint(resp)
+		},
+	}
+
+	return cmd
+}
@@ -2,13 +2,19 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
 	"github.com/spf13/cobra"
 )
 
@@ -41,16 +47,29 @@ type ToolCallParams struct {
 	Arguments map[string]interface{} `json:"arguments"`
 }
 
+// tokenEnv is the environment variable holding the bearer token when --token isn't given
+const tokenEnv = "BLUEPRINCE_MCP_TOKEN"
+
 // Client represents an MCP client for testing
 type Client struct {
 	configPath string
 	verbose    bool
+	// url is the streamable HTTP endpoint of a running server. When empty, each call runs its own server over stdio.
+	url string
+	// token authenticates with the server at url
+	token string
 }
 
 // NewClient creates a new MCP test client
 func NewClient(cmd *cobra.Command) (*Client, error) {
 	configPath, _ := cmd.Flags().GetString("config")
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	host, _ := cmd.Flags().GetString("host")
+	port, _ := cmd.Flags().GetInt("port")
+	token, _ := cmd.Flags().GetString("token")
+	if token == "" {
+		token = os.Getenv(tokenEnv)
+	}
 
 	// Make config path relative to current working directory if not absolute
 	if !filepath.IsAbs(configPath) {
@@ -98,10 +117,29 @@ func NewClient(cmd *cobra.Command) (*Client, error) {
 		fmt.Printf("Using config: %s\n", configPath)
 	}
 
-	return &Client{
+	c := &Client{
 		configPath: configPath,
 		verbose:    verbose,
-	}, nil
+		token:      token,
+	}
+
+	// Connect to a running server when --host or --port is given, filling in the other from the config.
+	// The server may be on another machine, so a config that doesn't validate here still leaves the defaults.
+	if host != "" || port != 0 {
+		var server config.ServerConfig
+		if cfg, err := config.LoadConfig(configPath); err == nil {
+			server = cfg.Server
+		}
+		if host != "" {
+			server.Host = host
+		}
+		if port != 0 {
+			server.Port = port
+		}
+		c.url = "http://" + server.Addr() + "/mcp"
+	}
+
+	return c, nil
 }
 
 // CallTool calls an MCP tool by running the server as a subprocess
@@ -124,6 +162,10 @@ func (c *Client) CallTool(toolName string, arguments map[string]interface{}) (*M
 		}
 	}
 
+	if c.url != "" {
+		return c.callToolHTTP(toolName, arguments)
+	}
+
 	// Marshal request to JSON
 	reqBody, err := json.Marshal(request)
 	if err != nil {
@@ -190,6 +232,57 @@ func (c *Client) CallTool(toolName string, arguments map[string]interface{}) (*M
 	return &mcpResp, nil
 }
 
+// callToolHTTP calls an MCP tool on the server listening at c.url with the streamable HTTP transport
+func (c *Client) callToolHTTP(toolName string, arguments map[string]interface{}) (*MCPResponse, error) {
+	if c.verbose {
+		fmt.Printf("Connecting to: %s\n", c.url)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var options []transport.StreamableHTTPCOption
+	if c.token != "" {
+		options = append(options, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + c.token}))
+	}
+	mcpClient, err := client.NewStreamableHttpClient(c.url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	defer mcpClient.Close()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "blueprince-tools", Version: "0.0.1"}
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		return nil, fmt.Errorf("failed to initialize with %s: %w", c.url, err)
+	}
+
+	callRequest := mcp.CallToolRequest{}
+	callRequest.Params.Name = toolName
+	callRequest.Params.Arguments = arguments
+	result, err := mcpClient.CallTool(ctx, callRequest)
+	if err != nil {
+		// JSON-RPC errors come back as Go errors, report them like the stdio responses
+		return &MCPResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: 1, Error: &MCPError{Message: err.Error()}}, nil
+	}
+
+	// Round trip the result so PrettyPrint sees the same shape as a stdio response
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	if c.verbose {
+		fmt.Printf("Response:\n%s\n", resultBytes)
+	}
+	var resp MCPResponse
+	resp.JSONRPC, resp.ID = mcp.JSONRPC_VERSION, 1
+	if err := json.Unmarshal(resultBytes, &resp.Result); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+	return &resp, nil
+}
+
 // PrettyPrint prints the result in a human-readable format
 func (c *Client) PrettyPrint(resp *MCPResponse) error {
 	if resp.Error != nil {
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newGuardLogCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "guard-log",
+		Short: "Summarize the content guard's decisions",
+		Long: `Summarizes the guard audit log in the vault's meta/ dir using the review_guard_log tool: checks, rejections,
+rule hits, and the same text being caught repeatedly.`,
+		Example: `  # Summarize the whole log
+  blueprince-tools guard-log
+
+  # Only decisions from a date on
+  blueprince-tools guard-log --since 2025-05-01`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := NewClient(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+
+			toolArgs := map[string]interface{}{}
+			if since != "" {
+				toolArgs["since"] = since
+			}
+			resp, err := client.CallTool("review_guard_log", toolArgs)
+			if err != nil {
+				return fmt.Errorf("failed to call review_guard_log: %w", err)
+			}
+
+			return client.PrettyPrint(resp)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only summarize decisions from this date (YYYY-MM-DD) or RFC3339 timestamp on")
+
+	return cmd
+}
@@ -24,6 +24,7 @@ and list operations on your notes.`,
 	rootCmd.PersistentFlags().String("config", "cmd/config/local/config.yaml", "Path to config file")
 	rootCmd.PersistentFlags().String("host", "", "Override server host")
 	rootCmd.PersistentFlags().Int("port", 0, "Override server port")
+	rootCmd.PersistentFlags().String("token", "", "Bearer token for a server running with --transport http (env: "+tokenEnv+")")
 	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output")
 
 	// Add subcommands
@@ -32,6 +33,11 @@ and list operations on your notes.`,
 	rootCmd.AddCommand(newReadCmd())
 	rootCmd.AddCommand(newUpdateCmd())
 	rootCmd.AddCommand(newDeleteCmd())
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.AddCommand(newRestoreCmd())
+	rootCmd.AddCommand(newSyncCmd())
+	rootCmd.AddCommand(newGuardLogCmd())
+	rootCmd.AddCommand(newTokenCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the vault from the newest Google Drive backup",
+		Long: `Rebuilds the vault from the newest snapshot in the vault_backups/ subfolder of the configured Google Drive folder using the restore_vault tool.
+Files in the snapshot overwrite local copies. Local files that aren't in the snapshot are kept.`,
+		Example: `  # Restore the vault
+  blueprince-tools restore`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := NewClient(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+
+			resp, err := client.CallTool("restore_vault", map[string]interface{}{})
+			if err != nil {
+				return fmt.Errorf("failed to call restore_vault: %w", err)
+			}
+
+			return client.PrettyPrint(resp)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newSyncCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Two-way sync notes with other devices",
+		Long: `Syncs the vault's notes/ dir with the vault_sync/ subfolder of the configured storage backend using the sync_vault tool.
+Notes edited on both devices since the last sync are kept side by side as "(Conflicted copy ...)" notes.`,
+		Example: `  # Preview what would change
+  blueprince-tools sync --dry-run
+
+  # Sync notes
+  blueprince-tools sync`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := NewClient(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+
+			resp, err := client.CallTool("sync_vault", map[string]interface{}{
+				"dry_run": dryRun,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to call sync_vault: %w", err)
+			}
+
+			return client.PrettyPrint(resp)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only report what would change")
+
+	return cmd
+}
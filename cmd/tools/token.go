@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/auth"
+	"github.com/spf13/cobra"
+)
+
+func newTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage the tokens clients use with the HTTP transports",
+		Long: `Creates, revokes and lists the bearer tokens that clients of the server's sse and http transports must send.
+Tokens are stored hashed in the secrets dir (google_drive_secrets_dir, or ~/.blueprince_mcp). A running server picks up changes right away.
+Unlike the other commands this works on the tokens file directly, so it doesn't need a running server.`,
+	}
+
+	cmd.PersistentFlags().String("secrets-dir", "", "Secrets dir holding the tokens file. Defaults to the one in the config")
+
+	cmd.AddCommand(newTokenCreateCmd())
+	cmd.AddCommand(newTokenRevokeCmd())
+	cmd.AddCommand(newTokenListCmd())
+
+	return cmd
+}
+
+func newTokenCreateCmd() *cobra.Command {
+	var scopes []string
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a token",
+		Long: fmt.Sprintf(`Creates a token with the given scopes and prints its secret. The secret is only shown once.
+Scopes: %s. admin allows every tool, including set_spoiler_level and the backup, restore and sync tools.`, strings.Join(auth.Scopes, ", ")),
+		Example: `  # A token for a client that only reads notes
+  blueprince-tools token create laptop --scope notes:read
+
+  # A token for a client that takes notes
+  blueprince-tools token create desktop --scope notes:read --scope notes:write --scope screenshots:import`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secretsDir, tokens, err := loadTokens(cmd)
+			if err != nil {
+				return err
+			}
+
+			token, secret, err := tokens.Create(args[0], scopes)
+			if err != nil {
+				return err
+			}
+			if err := tokens.Save(secretsDir); err != nil {
+				return err
+			}
+
+			fmt.Printf("✅ Created token '%s' (%s) with scopes: %s\n", token.Name, token.ID, strings.Join(token.Scopes, ", "))
+			fmt.Printf("Secret (shown only once):\n%s\n", secret)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&scopes, "scope", nil, fmt.Sprintf("Scope to grant, repeatable (%s)", strings.Join(auth.Scopes, ", ")))
+	cmd.MarkFlagRequired("scope")
+
+	return cmd
+}
+
+func newTokenRevokeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke <id or name>",
+		Short: "Revoke a token",
+		Example: `  # Revoke a token by name
+  blueprince-tools token revoke laptop`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secretsDir, tokens, err := loadTokens(cmd)
+			if err != nil {
+				return err
+			}
+
+			token, err := tokens.Revoke(args[0])
+			if err != nil {
+				return err
+			}
+			if err := tokens.Save(secretsDir); err != nil {
+				return err
+			}
+
+			fmt.Printf("✅ Revoked token '%s' (%s)\n", token.Name, token.ID)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newTokenListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tokens",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, tokens, err := loadTokens(cmd)
+			if err != nil {
+				return err
+			}
+
+			if len(tokens.Tokens) == 0 {
+				fmt.Println("No tokens. Create one with: blueprince-tools token create <name> --scope <scope>")
+				return nil
+			}
+			for _, token := range tokens.Tokens {
+				fmt.Printf("%s  %-20s  created %s  scopes: %s\n",
+					token.ID, token.Name, token.CreatedAt.Local().Format("2006-01-02 15:04"), strings.Join(token.Scopes, ", "))
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// loadTokens reads the tokens file in the --secrets-dir, or the secrets dir of the config
+func loadTokens(cmd *cobra.Command) (string, *auth.Tokens, error) {
+	secretsDir, _ := cmd.Flags().GetString("secrets-dir")
+	if secretsDir == "" {
+		client, err := NewClient(cmd)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to find the config, pass --secrets-dir instead: %w", err)
+		}
+		cfg, err := config.LoadConfig(client.configPath)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to load the config, pass --secrets-dir instead: %w", err)
+		}
+		if secretsDir, err = cfg.SecretsDir(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	tokens, err := auth.LoadTokens(secretsDir)
+	if err != nil {
+		return "", nil, err
+	}
+	return secretsDir, tokens, nil
+}
@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+)
+
+func TestTokens(t *testing.T) {
+	secretsDir := t.TempDir()
+
+	tokens, err := LoadTokens(secretsDir)
+	if err != nil || len(tokens.Tokens) != 0 {
+		t.Fatalf("LoadTokens() without a file = %v, %v", tokens, err)
+	}
+
+	token, secret, err := tokens.Create("laptop", []string{ScopeNotesWrite, ScopeNotesRead, ScopeNotesRead})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !strings.HasPrefix(secret, tokenPrefix) || token.Hash == secret || strings.Join(token.Scopes, ",") != "notes:read,notes:write" {
+		t.Errorf("Create() = %+v, %q", token, secret)
+	}
+	if _, _, err := tokens.Create("laptop", []string{ScopeNotesRead}); err == nil {
+		t.Error("Create() should not allow a duplicate name")
+	}
+	if _, _, err := tokens.Create("desktop", []string{"notes:delete"}); err == nil {
+		t.Error("Create() should not allow an unknown scope")
+	}
+	if _, _, err := tokens.Create("desktop", nil); err == nil {
+		t.Error("Create() should need a scope")
+	}
+	if err := tokens.Save(secretsDir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(TokensPath(secretsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), secret) {
+		t.Error("tokens file should not contain the secret")
+	}
+	if info, _ := os.Stat(TokensPath(secretsDir)); info.Mode().Perm() != 0600 {
+		t.Errorf("tokens file mode = %v, expected 0600", info.Mode().Perm())
+	}
+
+	v, err := NewVerifier(secretsDir)
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+	if got, err := v.Authenticate(secret); err != nil || got.Name != "laptop" {
+		t.Errorf("Authenticate() = %v, %v", got, err)
+	}
+	if _, err := v.Authenticate(secret + "x"); err != ErrInvalidToken {
+		t.Errorf("Authenticate() with a wrong secret error = %v", err)
+	}
+
+	// Revoking is picked up by the verifier without restarting
+	tokens, _ = LoadTokens(secretsDir)
+	if _, err := tokens.Revoke(token.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := tokens.Save(secretsDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Authenticate(secret); err != ErrInvalidToken {
+		t.Errorf("Authenticate() after revoking error = %v", err)
+	}
+	if _, err := tokens.Revoke("laptop"); err == nil {
+		t.Error("Revoke() should fail for a missing token")
+	}
+}
+
+func TestVerifier_SameModTime(t *testing.T) {
+	secretsDir := t.TempDir()
+	tokens, _ := LoadTokens(secretsDir)
+	laptop, laptopSecret, err := tokens.Create("laptop", []string{ScopeNotesRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.Save(secretsDir); err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewVerifier(secretsDir)
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	// Revoking one token and creating another right away is picked up even if the file keeps its modification time
+	if _, err := tokens.Revoke(laptop.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, desktopSecret, err := tokens.Create("desktop", []string{ScopeNotesRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.Save(secretsDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.Authenticate(laptopSecret); err != ErrInvalidToken {
+		t.Errorf("Authenticate() of the revoked token error = %v, expected %v", err, ErrInvalidToken)
+	}
+	if got, err := v.Authenticate(desktopSecret); err != nil || got.Name != "desktop" {
+		t.Errorf("Authenticate() of the new token = %v, %v", got, err)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	secretsDir := t.TempDir()
+	tokens, _ := LoadTokens(secretsDir)
+	_, secret, err := tokens.Create("reader", []string{ScopeNotesRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, importerSecret, err := tokens.Create("importer", []string{ScopeScreenshotsImport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.Save(secretsDir); err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewVerifier(secretsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), utils.LoggerKey, zap.NewNop())
+	var seen *Token
+	handler := HTTP(ctx, v, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = TokenFrom(r.Context())
+	}))
+
+	for _, header := range []string{"", "Bearer ", "Bearer nope", "Basic " + secret} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set("Authorization", header)
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status = %d, expected 401", header, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Authorization", "Bearer "+secret)
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || seen == nil || seen.Name != "reader" {
+		t.Errorf("valid token: status = %d, token = %v", rec.Code, seen)
+	}
+
+	// Resources and prompts return note content, so they need notes:read like the tools reading notes
+	tests := []struct {
+		secret   string
+		body     string
+		expected int
+	}{
+		{secret, `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"note://rooms/nook"}}`, http.StatusOK},
+		{secret, `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"log_room"}}`, http.StatusOK},
+		{importerSecret, `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"note://rooms/nook"}}`, http.StatusForbidden},
+		{importerSecret, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"note://rooms/nook"}}`, http.StatusForbidden},
+		{importerSecret, `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"log_room"}}`, http.StatusForbidden},
+		{importerSecret, `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","id":2,"method":"prompts/get"}]`, http.StatusForbidden},
+		{importerSecret, `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`, http.StatusOK},
+		{importerSecret, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"download_screenshots"}}`, http.StatusOK},
+		{importerSecret, `not json`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		var body string
+		handler := HTTP(ctx, v, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+		}))
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(tt.body))
+		req.Header.Set("Authorization", "Bearer "+tt.secret)
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.expected {
+			t.Errorf("%s: status = %d, expected %d", tt.body, rec.Code, tt.expected)
+		}
+		if rec.Code == http.StatusOK && body != tt.body {
+			t.Errorf("%s: next handler read %q, expected the whole body", tt.body, body)
+		}
+	}
+}
+
+func TestToolMiddleware(t *testing.T) {
+	ctx := context.WithValue(context.Background(), utils.LoggerKey, zap.NewNop())
+	handler := ToolMiddleware(ctx)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(ctx context.Context, tool string) bool {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool
+		result, err := handler(ctx, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return !result.IsError
+	}
+
+	reader := WithToken(context.Background(), &Token{Name: "reader", Scopes: []string{ScopeNotesRead}})
+	admin := WithToken(context.Background(), &Token{Name: "admin", Scopes: []string{ScopeAdmin}})
+	tests := []struct {
+		ctx      context.Context
+		tool     string
+		expected bool
+	}{
+		{context.Background(), "delete_note", true},
+		{reader, "read_note", true},
+		{reader, "delete_note", false},
+		{reader, "analyze_screenshot", false},
+		{reader, "ocr_screenshot", false},
+		{reader, "download_screenshots", false},
+		{reader, "some_new_tool", false},
+		{admin, "delete_note", true},
+		{admin, "some_new_tool", true},
+	}
+	for _, tt := range tests {
+		if got := call(tt.ctx, tt.tool); got != tt.expected {
+			token, _ := TokenFrom(tt.ctx)
+			t.Errorf("calling %s with %v allowed = %v, expected %v", tt.tool, token, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+)
+
+// ToolScopes is the scope each tool needs. Tools missing here need admin, so a new tool is never open by mistake.
+var ToolScopes = map[string]string{
+	"list_notes":                 ScopeNotesRead,
+	"read_note":                  ScopeNotesRead,
+	"is_discovered":              ScopeNotesRead,
+	"list_screenshots":           ScopeNotesRead,
+	"view_screenshot":            ScopeNotesRead,
+	"crop_screenshot":            ScopeNotesRead,
+	"find_similar_screenshots":   ScopeNotesRead,
+	"contact_sheet":              ScopeNotesRead,
+	"next_screenshot_to_analyze": ScopeNotesRead,
+	"search_screenshot_text":     ScopeNotesRead,
+	"create_note":                ScopeNotesWrite,
+	"update_note":                ScopeNotesWrite,
+	"delete_note":                ScopeNotesWrite,
+	"set_screenshot_status":      ScopeNotesWrite,
+	"analyze_screenshot":         ScopeNotesWrite, // marks the screenshot analyzed in the manifest
+	"ocr_screenshot":             ScopeNotesWrite, // stores the text it read in the manifest
+	"download_screenshots":       ScopeScreenshotsImport,
+	"set_spoiler_level":          ScopeAdmin,
+	"review_guard_log":           ScopeAdmin,
+	"backup_vault":               ScopeAdmin,
+	"restore_vault":              ScopeAdmin,
+	"sync_vault":                 ScopeAdmin,
+}
+
+// MethodScopes is the scope needed for requests other than tool calls that return note content.
+// Reading resources and getting prompts, which embed notes and screenshots, needs notes:read; listing them doesn't.
+var MethodScopes = map[string]string{
+	string(mcp.MethodResourcesRead): ScopeNotesRead,
+	"resources/subscribe":           ScopeNotesRead,
+	string(mcp.MethodPromptsGet):    ScopeNotesRead,
+}
+
+// ScopeFor returns the scope needed to call tool
+func ScopeFor(tool string) string {
+	if scope, ok := ToolScopes[tool]; ok {
+		return scope
+	}
+	return ScopeAdmin
+}
+
+type tokenKey struct{}
+
+// WithToken returns a copy of ctx carrying the token a request authenticated with
+func WithToken(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFrom returns the token the request behind ctx authenticated with, if any
+func TokenFrom(ctx context.Context) (*Token, bool) {
+	token, ok := ctx.Value(tokenKey{}).(*Token)
+	return token, ok
+}
+
+// HTTP wraps next so that only requests with a valid "Authorization: Bearer <token>" header reach it.
+// Requests for a method in MethodScopes are refused unless the token has its scope. Otherwise the token is added
+// to the request context, where ToolMiddleware checks the scopes of tool calls.
+func HTTP(ctx context.Context, v *Verifier, next http.Handler) http.Handler {
+	logger := utils.Logger(ctx)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || secret == "" {
+			unauthorized(w, "missing bearer token")
+			return
+		}
+		token, err := v.Authenticate(strings.TrimSpace(secret))
+		if err != nil {
+			logger.Warn("Rejected request", zap.String("remote", r.RemoteAddr), zap.Error(err))
+			unauthorized(w, ErrInvalidToken.Error())
+			return
+		}
+		for _, method := range requestMethods(r) {
+			if scope, ok := MethodScopes[method]; ok && !token.Allows(scope) {
+				logger.Warn("Token lacks the scope for method",
+					zap.String("token", token.Name),
+					zap.String("method", method),
+					zap.String("scope", scope))
+				http.Error(w, fmt.Sprintf("permission denied: token '%s' needs the '%s' scope for %s", token.Name, scope, method), http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(WithToken(r.Context(), token)))
+	})
+}
+
+// requestMethods returns the JSON-RPC methods in the body of a POST, a single message or a batch,
+// and puts the body back for the next handler. Bodies that don't parse are left for the MCP server to reject.
+func requestMethods(r *http.Request) []string {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	type message struct {
+		Method string `json:"method"`
+	}
+	var batch []message
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		json.Unmarshal(trimmed, &batch)
+	} else {
+		var single message
+		json.Unmarshal(trimmed, &single)
+		batch = append(batch, single)
+	}
+
+	methods := make([]string, 0, len(batch))
+	for _, m := range batch {
+		methods = append(methods, m.Method)
+	}
+	return methods
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="blueprince-mcp"`)
+	http.Error(w, message, http.StatusUnauthorized)
+}
+
+// ToolMiddleware checks that the token a tool call authenticated with has the tool's scope before its handler runs.
+// Calls without a token come over stdio from the process that started the server, and are allowed.
+func ToolMiddleware(ctx context.Context) server.ToolHandlerMiddleware {
+	logger := utils.Logger(ctx)
+
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			token, ok := TokenFrom(ctx)
+			if !ok {
+				return next(ctx, request)
+			}
+
+			scope := ScopeFor(request.Params.Name)
+			if !token.Allows(scope) {
+				logger.Warn("Token lacks the scope for tool",
+					zap.String("token", token.Name),
+					zap.String("tool", request.Params.Name),
+					zap.String("scope", scope))
+				return mcp.NewToolResultError(fmt.Sprintf("Permission denied: token '%s' needs the '%s' scope to call %s", token.Name, scope, request.Params.Name)), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
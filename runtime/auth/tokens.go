@@ -0,0 +1,256 @@
+// Package auth implements the bearer tokens that clients of the HTTP transports authenticate with
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+const (
+	// TOKENS_FILE is the file within the secrets dir that holds the hashed tokens
+	TOKENS_FILE = "mcp_tokens.json"
+	// tokenPrefix marks a secret as a token of this server, so one pasted in the wrong place is recognizable
+	tokenPrefix = "bp_"
+)
+
+// Scopes a token can be granted
+const (
+	ScopeNotesRead         = "notes:read"
+	ScopeNotesWrite        = "notes:write"
+	ScopeScreenshotsImport = "screenshots:import"
+	// ScopeAdmin allows everything, including the tools that change settings or replace the vault
+	ScopeAdmin = "admin"
+)
+
+// Scopes lists every scope
+var Scopes = []string{ScopeNotesRead, ScopeNotesWrite, ScopeScreenshotsImport, ScopeAdmin}
+
+// ErrInvalidToken is returned for a token that doesn't match any stored token
+var ErrInvalidToken = errors.New("invalid token")
+
+// Token is a stored token. Only the SHA-256 hash of its secret is kept: the secret is shown once, when it's created.
+type Token struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Allows reports whether the token was granted scope, or admin
+func (t *Token) Allows(scope string) bool {
+	return slices.Contains(t.Scopes, scope) || slices.Contains(t.Scopes, ScopeAdmin)
+}
+
+// Tokens is the tokens file
+type Tokens struct {
+	Tokens []Token `json:"tokens"`
+}
+
+// TokensPath returns the location of the tokens file within secretsDir
+func TokensPath(secretsDir string) string {
+	return filepath.Join(secretsDir, TOKENS_FILE)
+}
+
+// LoadTokens reads the tokens file in secretsDir. A missing file holds no tokens.
+func LoadTokens(secretsDir string) (*Tokens, error) {
+	data, err := readTokensFile(secretsDir)
+	if err != nil {
+		return nil, err
+	}
+	return parseTokens(data)
+}
+
+// readTokensFile returns the content of the tokens file in secretsDir, or nil if there is none yet
+func readTokensFile(secretsDir string) ([]byte, error) {
+	data, err := os.ReadFile(TokensPath(secretsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tokens file: %w", err)
+	}
+	return data, nil
+}
+
+func parseTokens(data []byte) (*Tokens, error) {
+	if data == nil {
+		return &Tokens{}, nil
+	}
+	var tokens Tokens
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse tokens file: %w", err)
+	}
+	return &tokens, nil
+}
+
+// Save writes the tokens file in secretsDir, readable only by its owner
+func (t *Tokens) Save(secretsDir string) error {
+	if err := utils.EnsureDirExists(secretsDir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tokens: %w", err)
+	}
+
+	path := TokensPath(secretsDir)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write tokens file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace tokens file: %w", err)
+	}
+	return nil
+}
+
+// Create adds a token named name with scopes, and returns it with its secret
+func (t *Tokens) Create(name string, scopes []string) (Token, string, error) {
+	if name == "" {
+		return Token{}, "", fmt.Errorf("tokens must have a name")
+	}
+	if t.find(name) != -1 {
+		return Token{}, "", fmt.Errorf("a token named '%s' already exists", name)
+	}
+	if len(scopes) == 0 {
+		return Token{}, "", fmt.Errorf("tokens need at least one scope. Must be some of: %v", Scopes)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return Token{}, "", fmt.Errorf("invalid scope '%s'. Must be one of: %v", scope, Scopes)
+		}
+	}
+
+	id, err := randomBytes(4)
+	if err != nil {
+		return Token{}, "", err
+	}
+	secretBytes, err := randomBytes(32)
+	if err != nil {
+		return Token{}, "", err
+	}
+	secret := tokenPrefix + base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	token := Token{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Hash:      utils.HashBytes([]byte(secret)),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		CreatedAt: time.Now().UTC(),
+	}
+	t.Tokens = append(t.Tokens, token)
+	return token, secret, nil
+}
+
+// Revoke removes the token with the given ID or name
+func (t *Tokens) Revoke(idOrName string) (Token, error) {
+	i := t.find(idOrName)
+	if i == -1 {
+		return Token{}, fmt.Errorf("no token with the ID or name '%s'", idOrName)
+	}
+	token := t.Tokens[i]
+	t.Tokens = slices.Delete(t.Tokens, i, i+1)
+	return token, nil
+}
+
+// Authenticate returns the token whose secret is secret
+func (t *Tokens) Authenticate(secret string) (*Token, error) {
+	hash := []byte(utils.HashBytes([]byte(secret)))
+	for i := range t.Tokens {
+		if subtle.ConstantTimeCompare(hash, []byte(t.Tokens[i].Hash)) == 1 {
+			return &t.Tokens[i], nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+func (t *Tokens) find(idOrName string) int {
+	return slices.IndexFunc(t.Tokens, func(token Token) bool { return token.ID == idOrName || token.Name == idOrName })
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	return b, nil
+}
+
+// Verifier authenticates secrets against the tokens file, reloading it when it changes,
+// so tokens created or revoked with the CLI take effect without restarting the server.
+type Verifier struct {
+	secretsDir string
+
+	mu sync.Mutex
+	// sum is the SHA-256 of the tokens file content tokens was parsed from, or empty if there was no file
+	sum    string
+	tokens *Tokens
+}
+
+// NewVerifier loads the tokens file in secretsDir
+func NewVerifier(secretsDir string) (*Verifier, error) {
+	v := &Verifier{secretsDir: secretsDir}
+	if _, err := v.current(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Count returns the number of tokens
+func (v *Verifier) Count() (int, error) {
+	tokens, err := v.current()
+	if err != nil {
+		return 0, err
+	}
+	return len(tokens.Tokens), nil
+}
+
+// Authenticate returns the token whose secret is secret
+func (v *Verifier) Authenticate(secret string) (*Token, error) {
+	tokens, err := v.current()
+	if err != nil {
+		return nil, err
+	}
+	return tokens.Authenticate(secret)
+}
+
+// current returns the tokens, parsing the file again if its content changed since it was last read.
+// The file is small, so it is read on every call: a token created or revoked within the resolution of the
+// file's modification time would go unnoticed by comparing that instead.
+func (v *Verifier) current() (*Tokens, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	data, err := readTokensFile(v.secretsDir)
+	if err != nil {
+		return nil, err
+	}
+	var sum string
+	if data != nil {
+		sum = utils.HashBytes(data)
+	}
+	if v.tokens != nil && sum == v.sum {
+		return v.tokens, nil
+	}
+
+	tokens, err := parseTokens(data)
+	if err != nil {
+		return nil, err
+	}
+	v.tokens, v.sum = tokens, sum
+	return tokens, nil
+}
@@ -0,0 +1,308 @@
+package guard
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// Decisions recorded in the audit log
+const (
+	DecisionAccepted = "accepted"
+	DecisionRejected = "rejected"
+)
+
+// auditMu serializes appends to the audit log within this process
+var auditMu sync.Mutex
+
+// AuditEntry is one guard decision: a line of the audit log
+type AuditEntry struct {
+	Time     time.Time      `json:"time"`
+	Tool     string         `json:"tool"`
+	Path     string         `json:"path"`
+	Decision string         `json:"decision"`
+	Findings []AuditFinding `json:"findings,omitempty"`
+}
+
+// AuditFinding is a rule hit. The matched text is stored as a hash so the log doesn't keep the spoilers it caught,
+// while repeated attempts at the same text can still be spotted.
+type AuditFinding struct {
+	Rule        string `json:"rule"`
+	Action      string `json:"action"`
+	ExcerptHash string `json:"excerpt_hash"`
+	Line        int    `json:"line,omitempty"`
+}
+
+// NewAuditEntry records the result of checking a write to the note at path by tool
+func NewAuditEntry(tool, path string, result *Result) AuditEntry {
+	entry := AuditEntry{
+		Time:     time.Now().UTC(),
+		Tool:     tool,
+		Path:     path,
+		Decision: DecisionAccepted,
+	}
+	if result.Rejected() {
+		entry.Decision = DecisionRejected
+	}
+	for _, f := range result.Findings {
+		entry.Findings = append(entry.Findings, AuditFinding{
+			Rule:        f.Rule,
+			Action:      f.Action,
+			ExcerptHash: utils.HashBytes([]byte(f.Match)),
+			Line:        f.Line,
+		})
+	}
+	return entry
+}
+
+// AuditPath returns the location of the audit log within the vault
+func AuditPath(vaultPath string) string {
+	return filepath.Join(vaultPath, vault.META_DIR, vault.GUARD_AUDIT_LOG)
+}
+
+// AppendAudit appends entry to the vault's audit log
+func AppendAudit(vaultPath string, entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	path := AuditPath(vaultPath)
+	if err := utils.EnsureDirExists(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// LoadAudit reads the audit log entries made at or after since. A zero since reads them all.
+// A missing log is not an error. Lines that don't parse, e.g. one cut short by a crash, are skipped and counted.
+func LoadAudit(vaultPath string, since time.Time) ([]AuditEntry, int, error) {
+	f, err := os.Open(AuditPath(vaultPath))
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			skipped++
+			continue
+		}
+		if entry.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, skipped, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, skipped, nil
+}
+
+// RuleCount is how often a rule fired, by action
+type RuleCount struct {
+	Rule     string
+	Total    int
+	ByAction map[string]int
+}
+
+// Repeat is the same text hitting the same rule more than once
+type Repeat struct {
+	Rule        string
+	ExcerptHash string
+	Count       int
+	Tools       []string
+	Paths       []string
+}
+
+// AuditSummary condenses audit log entries
+type AuditSummary struct {
+	From, To time.Time
+	Checks   int
+	Rejected int
+	// Warned and Stripped count accepted writes with at least one warning or strip
+	Warned   int
+	Stripped int
+	// ByTool counts checks and rejections per tool
+	ByTool       map[string][2]int
+	Rules        []RuleCount
+	Repeats      []Repeat
+	RejectedPath map[string]int
+}
+
+// Summarize counts the decisions, rule hits and repeated excerpts in entries
+func Summarize(entries []AuditEntry) AuditSummary {
+	summary := AuditSummary{ByTool: make(map[string][2]int), RejectedPath: make(map[string]int)}
+	rules := make(map[string]*RuleCount)
+	repeats := make(map[string]*Repeat)
+
+	for _, entry := range entries {
+		if summary.From.IsZero() || entry.Time.Before(summary.From) {
+			summary.From = entry.Time
+		}
+		if entry.Time.After(summary.To) {
+			summary.To = entry.Time
+		}
+		summary.Checks++
+		tool := summary.ByTool[entry.Tool]
+		tool[0]++
+		if entry.Decision == DecisionRejected {
+			summary.Rejected++
+			tool[1]++
+			summary.RejectedPath[entry.Path]++
+		}
+		summary.ByTool[entry.Tool] = tool
+
+		warned, stripped := false, false
+		for _, f := range entry.Findings {
+			warned = warned || f.Action == ActionWarn
+			stripped = stripped || f.Action == ActionStrip
+
+			rc, ok := rules[f.Rule]
+			if !ok {
+				rc = &RuleCount{Rule: f.Rule, ByAction: make(map[string]int)}
+				rules[f.Rule] = rc
+			}
+			rc.Total++
+			rc.ByAction[f.Action]++
+
+			key := f.Rule + "/" + f.ExcerptHash
+			r, ok := repeats[key]
+			if !ok {
+				r = &Repeat{Rule: f.Rule, ExcerptHash: f.ExcerptHash}
+				repeats[key] = r
+			}
+			r.Count++
+			r.Tools = appendUnique(r.Tools, entry.Tool)
+			r.Paths = appendUnique(r.Paths, entry.Path)
+		}
+		if entry.Decision == DecisionAccepted {
+			if warned {
+				summary.Warned++
+			}
+			if stripped {
+				summary.Stripped++
+			}
+		}
+	}
+
+	for _, rc := range rules {
+		summary.Rules = append(summary.Rules, *rc)
+	}
+	sort.Slice(summary.Rules, func(i, j int) bool {
+		if summary.Rules[i].Total != summary.Rules[j].Total {
+			return summary.Rules[i].Total > summary.Rules[j].Total
+		}
+		return summary.Rules[i].Rule < summary.Rules[j].Rule
+	})
+	for _, r := range repeats {
+		if r.Count > 1 {
+			summary.Repeats = append(summary.Repeats, *r)
+		}
+	}
+	sort.Slice(summary.Repeats, func(i, j int) bool {
+		if summary.Repeats[i].Count != summary.Repeats[j].Count {
+			return summary.Repeats[i].Count > summary.Repeats[j].Count
+		}
+		return summary.Repeats[i].ExcerptHash < summary.Repeats[j].ExcerptHash
+	})
+	return summary
+}
+
+// Format renders the summary as markdown
+func (s AuditSummary) Format() string {
+	if s.Checks == 0 {
+		return "No guard decisions recorded in this period."
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Guard Log: %s to %s\n\n", s.From.Local().Format(time.DateTime), s.To.Local().Format(time.DateTime))
+	fmt.Fprintf(&sb, "%d write(s) checked: %d rejected, %d accepted with warnings, %d accepted with content stripped.\n",
+		s.Checks, s.Rejected, s.Warned, s.Stripped)
+
+	sb.WriteString("\n## By tool\n")
+	for _, tool := range sortedKeys(s.ByTool) {
+		counts := s.ByTool[tool]
+		fmt.Fprintf(&sb, "- %s: %d checked, %d rejected\n", tool, counts[0], counts[1])
+	}
+
+	if len(s.Rules) > 0 {
+		sb.WriteString("\n## Rule hits\n")
+		for _, rc := range s.Rules {
+			var actions []string
+			for _, action := range sortedKeys(rc.ByAction) {
+				actions = append(actions, fmt.Sprintf("%d %s", rc.ByAction[action], action))
+			}
+			fmt.Fprintf(&sb, "- %s: %d (%s)\n", rc.Rule, rc.Total, strings.Join(actions, ", "))
+		}
+	}
+
+	if len(s.Repeats) > 0 {
+		sb.WriteString("\n## Repeated attempts\nThe same text hit the same rule more than once, e.g. a client retrying a rejected section:\n")
+		for _, r := range s.Repeats {
+			fmt.Fprintf(&sb, "- %s: %d times, excerpt %s, by %s in %s\n",
+				r.Rule, r.Count, r.ExcerptHash[:min(12, len(r.ExcerptHash))], strings.Join(r.Tools, ", "), strings.Join(r.Paths, ", "))
+		}
+	}
+
+	if len(s.RejectedPath) > 0 {
+		sb.WriteString("\n## Rejections by note\n")
+		paths := sortedKeys(s.RejectedPath)
+		sort.SliceStable(paths, func(i, j int) bool { return s.RejectedPath[paths[i]] > s.RejectedPath[paths[j]] })
+		for _, path := range paths {
+			fmt.Fprintf(&sb, "- %s: %d\n", path, s.RejectedPath[path])
+		}
+	}
+	return sb.String()
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
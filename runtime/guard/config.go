@@ -0,0 +1,148 @@
+package guard
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Rule types
+const (
+	TypeHeader     = "header"
+	TypeRegex      = "regex"
+	TypePhrase     = "phrase"
+	TypeProperNoun = "proper_noun"
+	TypeProvenance = "provenance"
+)
+
+// Types lists every rule type
+var Types = []string{TypeHeader, TypeRegex, TypePhrase, TypeProperNoun, TypeProvenance}
+
+// RuleConfig configures a rule. Patterns are header texts, regexes or phrases, depending on the type.
+type RuleConfig struct {
+	Name     string   `yaml:"name"`
+	Type     string   `yaml:"type,omitempty"`
+	Action   string   `yaml:"action,omitempty"`
+	Patterns []string `yaml:"patterns,omitempty"`
+}
+
+// Config holds the guard rules. The DefaultRules always run first: a configured rule with the same name changes
+// one of them (e.g. only its action, or "off" to disable it), and the others run after them.
+type Config struct {
+	Rules []RuleConfig `yaml:"rules,omitempty"`
+}
+
+// DefaultRules are the rules used when none are configured
+var DefaultRules = []RuleConfig{
+	{
+		Name:   "investigation_headers",
+		Type:   TypeHeader,
+		Action: ActionReject,
+		Patterns: []string{
+			"analysis", "investigation", "questions", "next steps", "follow-up", "follow up", "theory", "theories",
+			"connections", "clues", "mysteries", "research", "possible solution", "potential solution", "solution",
+			"hypothesis", "speculation", "things to try", "things to check", "to investigate", "to do", "todo",
+		},
+	},
+	{
+		Name:   "open_question_checklists",
+		Type:   TypeRegex,
+		Action: ActionStrip,
+		// Unchecked checklist items ending in a question, e.g. "- [ ] What opens the safe?"
+		Patterns: []string{`(?m)^[ \t]*[-*+][ \t]+\[ \][^\n]*\?[ \t]*(?:\n|$)`},
+	},
+	{
+		Name:   "solution_phrases",
+		Type:   TypePhrase,
+		Action: ActionWarn,
+		Patterns: []string{
+			"the solution is", "the answer is", "the code is", "you should try", "try checking", "worth investigating",
+			"worth checking", "this suggests", "this likely means", "this could mean",
+		},
+	},
+	{
+		Name:   "unknown_proper_nouns",
+		Type:   TypeProperNoun,
+		Action: ActionWarn,
+	},
+	{
+		Name:   "provenance_blocks",
+		Type:   TypeProvenance,
+		Action: ActionReject,
+	},
+}
+
+// Effective returns the DefaultRules merged with the configured rules
+func (c Config) Effective() []RuleConfig {
+	rules := slices.Clone(DefaultRules)
+	for _, configured := range c.Rules {
+		i := slices.IndexFunc(rules, func(rule RuleConfig) bool { return rule.Name == configured.Name })
+		if i == -1 {
+			rules = append(rules, configured)
+			continue
+		}
+		if configured.Type != "" {
+			rules[i].Type = configured.Type
+			rules[i].Patterns = nil
+		}
+		if configured.Action != "" {
+			rules[i].Action = configured.Action
+		}
+		if len(configured.Patterns) > 0 {
+			rules[i].Patterns = configured.Patterns
+		}
+	}
+	return rules
+}
+
+// Validate checks that every configured rule can be built
+func (c Config) Validate() error {
+	_, err := New(c, "")
+	return err
+}
+
+// New builds the pipeline for c. Proper noun rules check against the notes in vaultPath.
+func New(c Config, vaultPath string) (*Pipeline, error) {
+	var rules []Rule
+	for _, rc := range c.Effective() {
+		if rc.Name == "" {
+			return nil, fmt.Errorf("guard rules must have a name")
+		}
+		if !slices.Contains(Actions, rc.Action) {
+			return nil, fmt.Errorf("invalid action '%s' for guard rule '%s'. Must be one of: %v", rc.Action, rc.Name, Actions)
+		}
+		if rc.Action == ActionOff {
+			continue
+		}
+
+		rule, err := newRule(rc, vaultPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid guard rule '%s': %w", rc.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return NewPipeline(rules...), nil
+}
+
+func newRule(rc RuleConfig, vaultPath string) (Rule, error) {
+	if rc.Type != TypeProperNoun && rc.Type != TypeProvenance && len(rc.Patterns) == 0 {
+		return nil, fmt.Errorf("%s rules need at least one pattern", rc.Type)
+	}
+
+	switch rc.Type {
+	case TypeHeader:
+		return NewHeaderRule(rc.Name, rc.Action, rc.Patterns)
+	case TypeRegex:
+		if len(rc.Patterns) > 1 {
+			return nil, fmt.Errorf("regex rules take a single pattern, use one rule per regex")
+		}
+		return NewRegexRule(rc.Name, rc.Action, rc.Patterns[0])
+	case TypePhrase:
+		return NewPhraseRule(rc.Name, rc.Action, rc.Patterns)
+	case TypeProperNoun:
+		return NewProperNounRule(rc.Name, rc.Action, vaultPath)
+	case TypeProvenance:
+		return NewProvenanceRule(rc.Name, rc.Action)
+	default:
+		return nil, fmt.Errorf("invalid type '%s'. Must be one of: %v", rc.Type, Types)
+	}
+}
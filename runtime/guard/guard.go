@@ -0,0 +1,129 @@
+// Package guard checks note content written through the MCP tools before it reaches the vault.
+// A Pipeline runs a list of rules; each rule rejects the write, warns about it, or strips what it matched.
+package guard
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+)
+
+// Actions a rule takes on a match
+const (
+	// ActionReject fails the write
+	ActionReject = "reject"
+	// ActionWarn lets the write through and reports the match
+	ActionWarn = "warn"
+	// ActionStrip removes the match from the content and reports it
+	ActionStrip = "strip"
+	// ActionOff disables a rule, e.g. one of the default rules
+	ActionOff = "off"
+)
+
+// Actions lists every action a rule can be configured with
+var Actions = []string{ActionReject, ActionWarn, ActionStrip, ActionOff}
+
+// Document is a note about to be written
+type Document struct {
+	// Path is relative to the notes dir
+	Path     string
+	Metadata *notes.Metadata
+	// Content is the markdown body. Rules with ActionStrip rewrite it.
+	Content string
+}
+
+// Finding is a single rule match
+type Finding struct {
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+	// Match is the text that triggered the rule
+	Match string `json:"match"`
+	// Line is the 1-based line of the match in the content the rule checked, or 0 if it isn't tied to a line
+	Line int `json:"line,omitempty"`
+}
+
+// String formats the finding for a tool result, e.g. "[reject] investigation_headers: ... (line 3)"
+func (f Finding) String() string {
+	s := fmt.Sprintf("[%s] %s: %s", f.Action, f.Rule, f.Reason)
+	if f.Line > 0 {
+		s += fmt.Sprintf(" (line %d)", f.Line)
+	}
+	return s
+}
+
+// Rule is a single check in the pipeline
+type Rule interface {
+	Name() string
+	// Apply checks doc, rewriting doc.Content when the rule strips what it matches
+	Apply(doc *Document) ([]Finding, error)
+}
+
+// Result is the outcome of running the pipeline over a document
+type Result struct {
+	// Content is the content to write, with stripped matches removed
+	Content  string    `json:"-"`
+	Findings []Finding `json:"findings"`
+}
+
+// Rejected reports whether any rule rejected the write
+func (r *Result) Rejected() bool {
+	return slices.ContainsFunc(r.Findings, func(f Finding) bool { return f.Action == ActionReject })
+}
+
+// Report lists the findings with the given actions, one per line
+func (r *Result) Report(actions ...string) string {
+	var lines []string
+	for _, f := range r.Findings {
+		if slices.Contains(actions, f.Action) {
+			lines = append(lines, "- "+f.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON returns the findings as JSON, for clients that act on them
+func (r *Result) JSON() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// Pipeline runs rules over documents in order
+type Pipeline struct {
+	rules []Rule
+}
+
+// NewPipeline returns a pipeline running rules in the given order
+func NewPipeline(rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules}
+}
+
+// Rules returns the names of the pipeline's rules
+func (p *Pipeline) Rules() []string {
+	names := make([]string, len(p.rules))
+	for i, rule := range p.rules {
+		names[i] = rule.Name()
+	}
+	return names
+}
+
+// Check runs every rule over doc. Rules see the content as rewritten by the rules before them.
+// An error means a rule could not run, not that the content was rejected: see Result.Rejected.
+func (p *Pipeline) Check(doc Document) (*Result, error) {
+	result := &Result{}
+	for _, rule := range p.rules {
+		findings, err := rule.Apply(&doc)
+		if err != nil {
+			return nil, fmt.Errorf("guard rule '%s' failed: %w", rule.Name(), err)
+		}
+		result.Findings = append(result.Findings, findings...)
+	}
+	result.Content = doc.Content
+	return result, nil
+}
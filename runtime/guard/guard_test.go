@@ -0,0 +1,285 @@
+package guard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+)
+
+func TestHeaderRule(t *testing.T) {
+	rule, err := NewHeaderRule("headers", ActionReject, []string{"analysis", "possible solution", "next steps"})
+	if err != nil {
+		t.Fatalf("NewHeaderRule() error = %v", err)
+	}
+
+	tests := []struct {
+		content  string
+		expected bool
+	}{
+		{"## Analysis\nThe tiger means something", true},
+		{"**Analysis:** the tiger means something", true},
+		{"- **Possible Solutions**: try the safe", true},
+		{"### possible solution", true},
+		{"Next steps:\n- go back", true},
+		{"# Nook - Tiger Paintings\n\nPaintings of tiger", false},
+		{"I did some analysis of the paintings", false},
+		{"**Bold** text in a sentence", false},
+		{"## Analyst's desk", false},
+	}
+	for _, tt := range tests {
+		findings, err := rule.Apply(&Document{Content: tt.content})
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if got := len(findings) > 0; got != tt.expected {
+			t.Errorf("Apply(%q) matched = %v, expected %v", tt.content, got, tt.expected)
+		}
+	}
+}
+
+func TestHeaderRule_Strip(t *testing.T) {
+	rule, err := NewHeaderRule("headers", ActionStrip, []string{"analysis"})
+	if err != nil {
+		t.Fatalf("NewHeaderRule() error = %v", err)
+	}
+
+	doc := &Document{Content: "# Nook\n\nTwo tigers.\n\n## Analysis\nTigers mean X.\n### Details\nMore.\n\n## Seen\nA cupcake stand."}
+	findings, err := rule.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Line != 5 {
+		t.Fatalf("Apply() findings = %+v, expected one on line 5", findings)
+	}
+	expected := "# Nook\n\nTwo tigers.\n\n## Seen\nA cupcake stand."
+	if doc.Content != expected {
+		t.Errorf("Apply() content = %q, expected %q", doc.Content, expected)
+	}
+}
+
+func TestRegexRule_Strip(t *testing.T) {
+	rule, err := NewRegexRule("checklists", ActionStrip, DefaultRules[1].Patterns[0])
+	if err != nil {
+		t.Fatalf("NewRegexRule() error = %v", err)
+	}
+
+	doc := &Document{Content: "Two tigers.\n- [ ] What do they mean?\n- [ ] Check the safe\n- [x] Why?"}
+	findings, err := rule.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Line != 2 {
+		t.Fatalf("Apply() findings = %+v, expected one on line 2", findings)
+	}
+	expected := "Two tigers.\n- [ ] Check the safe\n- [x] Why?"
+	if doc.Content != expected {
+		t.Errorf("Apply() content = %q, expected %q", doc.Content, expected)
+	}
+}
+
+func TestPhraseRule(t *testing.T) {
+	rule, err := NewPhraseRule("phrases", ActionStrip, []string{"The solution is"})
+	if err != nil {
+		t.Fatalf("NewPhraseRule() error = %v", err)
+	}
+
+	doc := &Document{Content: "Safe on the desk.\nthe solution is 1234.\nLocked."}
+	findings, err := rule.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Line != 2 {
+		t.Fatalf("Apply() findings = %+v, expected one on line 2", findings)
+	}
+	if doc.Content != "Safe on the desk.\nLocked." {
+		t.Errorf("Apply() content = %q", doc.Content)
+	}
+}
+
+func TestProperNounRule(t *testing.T) {
+	vaultPath := t.TempDir()
+	roomsDir := filepath.Join(vaultPath, "notes", "rooms")
+	if err := os.MkdirAll(roomsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Names only in an external block were added by the assistant, so they don't count as known
+	existing := "---\ntitle: Nook\ntags: [simon_jones]\n---\n\nTiger paintings in the Nook.\n\n> [!external] Background\n> Ellen Crane painted them.\n"
+	if err := os.WriteFile(filepath.Join(roomsDir, "nook.md"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rule, err := NewProperNounRule("names", ActionWarn, vaultPath)
+	if err != nil {
+		t.Fatalf("NewProperNounRule() error = %v", err)
+	}
+
+	doc := &Document{
+		Metadata: &notes.Metadata{Title: "Parlor Boxes", Tags: []string{"blue_box"}},
+		Content: strings.Join([]string{
+			"# Parlor Boxes",
+			"The Blue Box and the Nook paintings.",
+			"Simon Jones wrote a note. I think Mary Smith signed it.",
+			"Another line mentions Mary Smith again.",
+			"Ellen Crane left a key.",
+		}, "\n"),
+	}
+	findings, err := rule.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(findings) != 2 || findings[0].Match != "Mary Smith" || findings[0].Line != 3 || findings[1].Match != "Ellen Crane" {
+		t.Errorf("Apply() findings = %+v, expected 'Mary Smith' on line 3 and 'Ellen Crane'", findings)
+	}
+
+	// A note written after the known names were cached makes its names known
+	if err := os.WriteFile(filepath.Join(roomsDir, "parlor.md"), []byte("Mary Smith signed the letter.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	findings, err = rule.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Match != "Ellen Crane" {
+		t.Errorf("Apply() findings = %+v, expected only 'Ellen Crane' once the new note is written", findings)
+	}
+
+	if _, err := NewProperNounRule("names", ActionStrip, vaultPath); err == nil {
+		t.Error("NewProperNounRule() should not allow stripping")
+	}
+}
+
+func TestProvenanceRule(t *testing.T) {
+	rule, err := NewProvenanceRule("provenance", ActionReject)
+	if err != nil {
+		t.Fatalf("NewProvenanceRule() error = %v", err)
+	}
+
+	doc := &Document{Content: "Two tigers.\n```source: wiki\nTigers are X.\n```\n> [!external]\n> Fine.\n```source: screenshot\nNever closed"}
+	findings, err := rule.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(findings) != 2 || findings[0].Line != 2 || findings[1].Line != 7 {
+		t.Errorf("Apply() findings = %+v, expected the unknown source on line 2 and the unclosed fence on line 7", findings)
+	}
+}
+
+func TestNew(t *testing.T) {
+	pipeline, err := New(Config{}, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if len(pipeline.Rules()) != len(DefaultRules) {
+		t.Errorf("New() rules = %v, expected the %d default rules", pipeline.Rules(), len(DefaultRules))
+	}
+
+	cfg := Config{Rules: []RuleConfig{
+		{Name: "investigation_headers", Action: ActionWarn},
+		{Name: "unknown_proper_nouns", Action: ActionOff},
+		{Name: "codes", Type: TypeRegex, Action: ActionReject, Patterns: []string{`\b\d{4}\b`}},
+	}}
+	pipeline, err = New(cfg, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	expected := []string{"investigation_headers", "open_question_checklists", "solution_phrases", "provenance_blocks", "codes"}
+	if got := pipeline.Rules(); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("New() rules = %v, expected %v", got, expected)
+	}
+
+	result, err := pipeline.Check(Document{Content: "## Analysis\nThe code is 1234"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !result.Rejected() {
+		t.Error("Check() should reject the code")
+	}
+	if report := result.Report(ActionWarn); !strings.Contains(report, "investigation_headers") || !strings.Contains(report, "solution_phrases") {
+		t.Errorf("Check() warnings = %q, expected the header and phrase rules", report)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	invalid := []Config{
+		{Rules: []RuleConfig{{Name: "bad_regex", Type: TypeRegex, Action: ActionWarn, Patterns: []string{"("}}}},
+		{Rules: []RuleConfig{{Name: "bad_action", Type: TypePhrase, Action: "delete", Patterns: []string{"x"}}}},
+		{Rules: []RuleConfig{{Name: "bad_type", Type: "words", Action: ActionWarn, Patterns: []string{"x"}}}},
+		{Rules: []RuleConfig{{Name: "no_patterns", Type: TypeHeader, Action: ActionWarn}}},
+		{Rules: []RuleConfig{{Type: TypePhrase, Action: ActionWarn, Patterns: []string{"x"}}}},
+	}
+	for _, cfg := range invalid {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", cfg.Rules[0])
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	vaultPath := t.TempDir()
+
+	pipeline, err := New(Config{}, "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, write := range []struct{ tool, path, content string }{
+		{"create_note", "rooms/nook.md", "Two tigers.\n## Analysis\nthey mean something."},
+		{"update_note", "rooms/nook.md", "Two tigers.\n## Analysis\nthey mean something."},
+		{"create_note", "rooms/parlor.md", "Three boxes.\n- [ ] Which one is true?"},
+		{"create_note", "rooms/study.md", "A desk."},
+	} {
+		result, err := pipeline.Check(Document{Path: write.path, Content: write.content})
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if err := AppendAudit(vaultPath, NewAuditEntry(write.tool, write.path, result)); err != nil {
+			t.Fatalf("AppendAudit() error = %v", err)
+		}
+	}
+
+	// A line cut short by a crash is skipped
+	f, err := os.OpenFile(AuditPath(vaultPath), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2025-`)
+	f.Close()
+
+	data, err := os.ReadFile(AuditPath(vaultPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "mean something") || strings.Contains(string(data), "Analysis") {
+		t.Error("audit log should not contain the matched text")
+	}
+
+	entries, skipped, err := LoadAudit(vaultPath, time.Time{})
+	if err != nil {
+		t.Fatalf("LoadAudit() error = %v", err)
+	}
+	if len(entries) != 4 || skipped != 1 {
+		t.Fatalf("LoadAudit() = %d entries, %d skipped, expected 4 and 1", len(entries), skipped)
+	}
+
+	summary := Summarize(entries)
+	if summary.Checks != 4 || summary.Rejected != 2 || summary.Stripped != 1 || summary.Warned != 0 {
+		t.Errorf("Summarize() = %+v", summary)
+	}
+	if len(summary.Repeats) != 1 || summary.Repeats[0].Rule != "investigation_headers" || summary.Repeats[0].Count != 2 ||
+		strings.Join(summary.Repeats[0].Tools, ",") != "create_note,update_note" {
+		t.Errorf("Summarize() repeats = %+v, expected the analysis header twice", summary.Repeats)
+	}
+	if summary.RejectedPath["rooms/nook.md"] != 2 {
+		t.Errorf("Summarize() rejections = %v", summary.RejectedPath)
+	}
+
+	if entries, _, _ := LoadAudit(vaultPath, time.Now().Add(time.Hour)); len(entries) != 0 {
+		t.Errorf("LoadAudit() since later = %d entries, expected none", len(entries))
+	}
+	if entries, _, err := LoadAudit(t.TempDir(), time.Time{}); err != nil || len(entries) != 0 {
+		t.Errorf("LoadAudit() without a log = %v, %v", entries, err)
+	}
+}
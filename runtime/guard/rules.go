@@ -0,0 +1,414 @@
+package guard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+var (
+	markdownHeaderRe = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)[\s#]*$`)
+	// boldLabelRe matches lines starting with a bold label, e.g. "**Analysis:**" or "- **Possible solution**: ..."
+	boldLabelRe = regexp.MustCompile(`^\s*(?:[-*+]\s+)?(?:\*\*|__)(.+?)(?:\*\*|__)(.*)$`)
+	// plainLabelRe matches lines holding only a short label and a colon, e.g. "Next steps:"
+	plainLabelRe = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9 '\-]{0,40}):\s*$`)
+	// properNounRe matches runs of capitalized words, e.g. "Simon Jones" or "Nook"
+	properNounRe = regexp.MustCompile(`[A-Z][A-Za-z'’]*(?:[ \t]+[A-Z][A-Za-z'’]*)*`)
+)
+
+// labelLevel is the section level of bold and plain labels: their section ends at the next header or label of any level
+const labelLevel = 7
+
+// notProperNouns are capitalized words that are not names wherever they appear
+var notProperNouns = []string{"I", "I'm", "I've", "I'd", "I'll", "OK"}
+
+// HeaderRule matches section headers, whether markdown headers, bold labels or "Label:" lines, that start with one of
+// its patterns. Matching ignores case, punctuation and plurals. Stripping removes the whole section.
+type HeaderRule struct {
+	name     string
+	action   string
+	patterns []*regexp.Regexp
+}
+
+// NewHeaderRule returns a HeaderRule for the given header texts, e.g. "analysis" or "possible solution"
+func NewHeaderRule(name, action string, patterns []string) (*HeaderRule, error) {
+	rule := &HeaderRule{name: name, action: action}
+	for _, pattern := range patterns {
+		words := strings.Fields(strings.ToLower(pattern))
+		if len(words) == 0 {
+			return nil, fmt.Errorf("empty header pattern")
+		}
+		quoted := make([]string, len(words))
+		for i, word := range words {
+			quoted[i] = regexp.QuoteMeta(word)
+		}
+		rule.patterns = append(rule.patterns, regexp.MustCompile(`^`+strings.Join(quoted, `\s+`)+`(?:s|es)?\b`))
+	}
+	return rule, nil
+}
+
+func (r *HeaderRule) Name() string { return r.name }
+
+func (r *HeaderRule) Apply(doc *Document) ([]Finding, error) {
+	var findings []Finding
+	lines := strings.Split(doc.Content, "\n")
+	kept := make([]string, 0, len(lines))
+	// stripLevel is the level of the section being stripped, or 0 outside of one
+	stripLevel := 0
+	for i, line := range lines {
+		level, text, isHeader := parseHeader(line)
+		if stripLevel > 0 {
+			if !isHeader || level > stripLevel {
+				continue
+			}
+			stripLevel = 0
+		}
+		if isHeader && r.matches(text) {
+			findings = append(findings, Finding{
+				Rule:   r.name,
+				Action: r.action,
+				Reason: fmt.Sprintf("content has a section headed '%s', which adds analysis the player did not write", text),
+				Match:  strings.TrimSpace(line),
+				Line:   i + 1,
+			})
+			if r.action == ActionStrip {
+				stripLevel = level
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+	if r.action == ActionStrip {
+		doc.Content = strings.Join(kept, "\n")
+	}
+	return findings, nil
+}
+
+func (r *HeaderRule) matches(text string) bool {
+	return slices.ContainsFunc(r.patterns, func(pattern *regexp.Regexp) bool { return pattern.MatchString(text) })
+}
+
+// parseHeader returns the section level and normalized text of a header line
+func parseHeader(line string) (int, string, bool) {
+	if m := markdownHeaderRe.FindStringSubmatch(line); m != nil {
+		return len(m[1]), normalizeLabel(m[2]), true
+	}
+	if m := boldLabelRe.FindStringSubmatch(line); m != nil {
+		label, rest := strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+		if strings.HasSuffix(label, ":") || strings.HasPrefix(rest, ":") || rest == "" {
+			return labelLevel, normalizeLabel(label), true
+		}
+	}
+	if m := plainLabelRe.FindStringSubmatch(line); m != nil {
+		return labelLevel, normalizeLabel(m[1]), true
+	}
+	return 0, "", false
+}
+
+// normalizeLabel lowercases a header and trims its markup, e.g. "**Possible Solutions:**" -> "possible solutions"
+func normalizeLabel(text string) string {
+	return strings.ToLower(strings.Trim(text, " \t*_:`"))
+}
+
+// RegexRule matches a regular expression. Stripping removes the matched text.
+type RegexRule struct {
+	name   string
+	action string
+	re     *regexp.Regexp
+}
+
+// NewRegexRule returns a RegexRule for pattern, in Go regexp syntax
+func NewRegexRule(name, action, pattern string) (*RegexRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex '%s': %w", pattern, err)
+	}
+	return &RegexRule{name: name, action: action, re: re}, nil
+}
+
+func (r *RegexRule) Name() string { return r.name }
+
+func (r *RegexRule) Apply(doc *Document) ([]Finding, error) {
+	var findings []Finding
+	for _, loc := range r.re.FindAllStringIndex(doc.Content, -1) {
+		match := doc.Content[loc[0]:loc[1]]
+		if strings.TrimSpace(match) == "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:   r.name,
+			Action: r.action,
+			Reason: fmt.Sprintf("content matches /%s/", r.re.String()),
+			Match:  strings.TrimSpace(match),
+			Line:   strings.Count(doc.Content[:loc[0]], "\n") + 1,
+		})
+	}
+	if r.action == ActionStrip && len(findings) > 0 {
+		doc.Content = r.re.ReplaceAllString(doc.Content, "")
+	}
+	return findings, nil
+}
+
+// PhraseRule matches forbidden phrases, ignoring case. Stripping removes the lines containing them.
+type PhraseRule struct {
+	name    string
+	action  string
+	phrases []string
+}
+
+// NewPhraseRule returns a PhraseRule for phrases
+func NewPhraseRule(name, action string, phrases []string) (*PhraseRule, error) {
+	rule := &PhraseRule{name: name, action: action}
+	for _, phrase := range phrases {
+		phrase = strings.ToLower(strings.TrimSpace(phrase))
+		if phrase == "" {
+			return nil, fmt.Errorf("empty phrase")
+		}
+		rule.phrases = append(rule.phrases, phrase)
+	}
+	return rule, nil
+}
+
+func (r *PhraseRule) Name() string { return r.name }
+
+func (r *PhraseRule) Apply(doc *Document) ([]Finding, error) {
+	var findings []Finding
+	lines := strings.Split(doc.Content, "\n")
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		lower := strings.ToLower(line)
+		matched := false
+		for _, phrase := range r.phrases {
+			if strings.Contains(lower, phrase) {
+				findings = append(findings, Finding{
+					Rule:   r.name,
+					Action: r.action,
+					Reason: fmt.Sprintf("content contains the phrase '%s'", phrase),
+					Match:  strings.TrimSpace(line),
+					Line:   i + 1,
+				})
+				matched = true
+			}
+		}
+		if !matched || r.action != ActionStrip {
+			kept = append(kept, line)
+		}
+	}
+	if r.action == ActionStrip {
+		doc.Content = strings.Join(kept, "\n")
+	}
+	return findings, nil
+}
+
+// ProperNounRule flags names that appear in none of the player's existing notes, nor in the note's own title,
+// primary subject or tags. A name the client brings in from outside the notes is a likely spoiler.
+// A single capitalized word starting a sentence is not taken as a name. The rule can reject or warn, but not strip.
+type ProperNounRule struct {
+	name      string
+	action    string
+	vaultPath string
+
+	mu sync.Mutex
+	// stamp is the notesStamp known was built from
+	stamp string
+	// known holds the slugified text of every note, joined and wrapped in underscores
+	known string
+}
+
+// NewProperNounRule returns a ProperNounRule checking against the notes in vaultPath
+func NewProperNounRule(name, action, vaultPath string) (*ProperNounRule, error) {
+	if action == ActionStrip {
+		return nil, fmt.Errorf("proper noun rules can only reject or warn")
+	}
+	return &ProperNounRule{name: name, action: action, vaultPath: vaultPath}, nil
+}
+
+func (r *ProperNounRule) Name() string { return r.name }
+
+func (r *ProperNounRule) Apply(doc *Document) ([]Finding, error) {
+	notesKnown, err := r.knownNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var known strings.Builder
+	known.WriteString(notesKnown)
+	if doc.Metadata != nil {
+		for _, text := range append([]string{doc.Metadata.Title, doc.Metadata.PrimarySubject}, doc.Metadata.Tags...) {
+			known.WriteString(utils.Slugify(text))
+			known.WriteString("_")
+		}
+	}
+	haystack := known.String()
+
+	var findings []Finding
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(doc.Content, "\n") {
+		for _, name := range properNouns(line) {
+			key := utils.Slugify(name.text)
+			if key == "" || seen[key] || isKnown(haystack, key) {
+				continue
+			}
+			if name.startsSentence && isKnown(haystack, utils.Slugify(name.rest())) {
+				// e.g. "The Nook" where only "Nook" is a name
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, Finding{
+				Rule:   r.name,
+				Action: r.action,
+				Reason: fmt.Sprintf("'%s' does not appear in any of the player's notes. Only write names the player gave", name.text),
+				Match:  name.text,
+				Line:   i + 1,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// knownNames returns the slugified text of every note, reloading the notes only when one was added, removed or changed
+func (r *ProperNounRule) knownNames() (string, error) {
+	stamp, err := notesStamp(r.vaultPath)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.known != "" && stamp == r.stamp {
+		return r.known, nil
+	}
+
+	all, err := notes.LoadAll(r.vaultPath)
+	if err != nil {
+		return "", err
+	}
+	// Names the assistant added in external blocks don't make them known to the player
+	var known strings.Builder
+	known.WriteString("_")
+	for _, note := range all {
+		content, _ := notes.StripExternal(note.Content)
+		known.WriteString(utils.Slugify(content))
+		known.WriteString("_")
+	}
+	r.stamp, r.known = stamp, known.String()
+	return r.known, nil
+}
+
+// notesStamp sums up the path, modification time and size of every note in the vault, which changes whenever a note does
+func notesStamp(vaultPath string) (string, error) {
+	notesDir := filepath.Join(vaultPath, vault.NOTES_DIR)
+	if _, err := os.Stat(notesDir); os.IsNotExist(err) {
+		return "", nil
+	}
+	files, err := utils.ListFiles(notesDir)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, rel := range files {
+		if filepath.Ext(rel) != ".md" {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(notesDir, rel))
+		if err != nil {
+			// Removed since the listing, which the next write will see
+			continue
+		}
+		fmt.Fprintf(&sb, "%s %d %d\n", rel, info.ModTime().UnixNano(), info.Size())
+	}
+	return sb.String(), nil
+}
+
+// properNoun is a run of capitalized words in a line
+type properNoun struct {
+	text string
+	// startsSentence is set when the first word may only be capitalized because it starts a sentence
+	startsSentence bool
+}
+
+// rest returns the words after the first
+func (n properNoun) rest() string {
+	_, rest, _ := strings.Cut(n.text, " ")
+	return rest
+}
+
+// properNouns returns the names in a line of markdown. A single capitalized word starting a sentence is not taken
+// as a name.
+func properNouns(line string) []properNoun {
+	var names []properNoun
+	for _, loc := range properNounRe.FindAllStringIndex(line, -1) {
+		words := strings.Fields(line[loc[0]:loc[1]])
+		startsSentence := sentenceStart(line[:loc[0]]) && !slices.Contains(notProperNouns, words[0])
+		words = slices.DeleteFunc(words, func(word string) bool { return slices.Contains(notProperNouns, word) })
+		if len(words) == 0 || (startsSentence && len(words) == 1) {
+			continue
+		}
+		names = append(names, properNoun{text: strings.Join(words, " "), startsSentence: startsSentence})
+	}
+	return names
+}
+
+// sentenceStart reports whether text ending in before is followed by the start of a sentence
+func sentenceStart(before string) bool {
+	before = strings.TrimRight(before, " \t*_\"'([")
+	if strings.TrimLeft(before, " \t#>-*+|0123456789.") == "" {
+		// Start of a line, header, quote, list item or table cell
+		return true
+	}
+	// "]" ends a callout marker or a link, e.g. "> [!screenshot] Letter"
+	return strings.ContainsAny(before[len(before)-1:], ".!?:|]")
+}
+
+// isKnown reports whether the words of key, or of its singular or plural, appear in haystack
+func isKnown(haystack, key string) bool {
+	for _, variant := range []string{key, key + "s", key + "es", strings.TrimSuffix(key, "s")} {
+		if strings.Contains(haystack, "_"+variant+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// ProvenanceRule flags malformed provenance blocks: fences tagged with an unknown source, or never closed.
+// The rule can reject or warn, but not strip.
+type ProvenanceRule struct {
+	name   string
+	action string
+}
+
+// NewProvenanceRule returns a ProvenanceRule
+func NewProvenanceRule(name, action string) (*ProvenanceRule, error) {
+	if action == ActionStrip {
+		return nil, fmt.Errorf("provenance rules can only reject or warn")
+	}
+	return &ProvenanceRule{name: name, action: action}, nil
+}
+
+func (r *ProvenanceRule) Name() string { return r.name }
+
+func (r *ProvenanceRule) Apply(doc *Document) ([]Finding, error) {
+	_, issues := notes.ParseProvenance(doc.Content)
+	lines := strings.Split(doc.Content, "\n")
+
+	findings := make([]Finding, len(issues))
+	for i, issue := range issues {
+		findings[i] = Finding{
+			Rule:   r.name,
+			Action: r.action,
+			Reason: "malformed provenance block: " + issue.Message,
+			Match:  strings.TrimSpace(lines[issue.Line-1]),
+			Line:   issue.Line,
+		}
+	}
+	return findings, nil
+}
@@ -2,50 +2,209 @@ package runtime
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/myungbeans/blueprince-mcp/cmd/config"
-	"github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/files"
+	"github.com/myungbeans/blueprince-mcp/runtime/auth"
+	"github.com/myungbeans/blueprince-mcp/runtime/guard"
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/prompts"
+	discoveryResources "github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/discoveries"
+	noteResources "github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/notes"
 	"github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/rules"
+	screenshotResources "github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/subscriptions"
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/tools/backup"
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/tools/discoveries"
 	"github.com/myungbeans/blueprince-mcp/runtime/mcp/tools/notes"
 	"github.com/myungbeans/blueprince-mcp/runtime/mcp/tools/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/tools/spoilers"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/ocr"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
 
 	"github.com/mark3labs/mcp-go/server"
 )
 
 type Handler struct {
-	cfg   *config.Config
-	store storage.Store
+	cfg           *config.Config
+	store         storage.Store
+	hooks         *server.Hooks
+	subscriptions *subscriptions.Set
 }
 
 func NewHandler(cfg *config.Config, store storage.Store) *Handler {
 	return &Handler{
 		cfg:   cfg,
 		store: store,
+		hooks: &server.Hooks{},
 	}
 }
 
+// Hooks returns the hooks the MCP server must be created with, through server.WithHooks,
+// so that resource subscriptions end along with their client's session
+func (h *Handler) Hooks() *server.Hooks {
+	return h.hooks
+}
+
 func (h *Handler) RegisterTools(ctx context.Context, s *server.MCPServer) {
+	// Note content written through the tools is checked by the guard pipeline
+	g, err := guard.New(h.cfg.Guard, h.cfg.ObsidianVaultPath)
+	if err != nil {
+		// LoadConfig validates the guard rules, so only a config built elsewhere gets here
+		utils.Logger(ctx).Error("Invalid guard rules, using the defaults", zap.Error(err))
+		g, _ = guard.New(guard.Config{}, h.cfg.ObsidianVaultPath)
+	}
+
 	// Register Tools
 	s.AddTool(notes.ListTool(), notes.ListHandler(ctx, h.cfg))
-	s.AddTool(notes.CreateTool(), notes.CreateHandler(ctx, h.cfg))
+	s.AddTool(notes.CreateTool(), notes.CreateHandler(ctx, h.cfg, g))
 	s.AddTool(notes.ReadTool(), notes.ReadHandler(ctx, h.cfg))
-	s.AddTool(notes.UpdateTool(), notes.UpdateHandler(ctx, h.cfg))
+	s.AddTool(notes.UpdateTool(), notes.UpdateHandler(ctx, h.cfg, g))
 	s.AddTool(notes.DeleteTool(), notes.DeleteHandler(ctx, h.cfg))
+	s.AddTool(notes.ReviewGuardLogTool(), notes.ReviewGuardLogHandler(ctx, h.cfg))
 	s.AddTool(screenshots.DownloadTool(), screenshots.DownloadHandler(ctx, h.store))
 	s.AddTool(screenshots.ListTool(), screenshots.ListHandler(ctx, h.cfg, h.store))
-	// TODO: need to figure out image compression s.AddTool(screenshots.ViewTool(), screenshots.ViewHandler(ctx, h.cfg))
+	s.AddTool(screenshots.ViewTool(), screenshots.ViewHandler(ctx, h.cfg))
 	s.AddTool(screenshots.AnalyzeTool(), screenshots.AnalyzeHandler(ctx, h.cfg))
+	s.AddTool(screenshots.CropTool(), screenshots.CropHandler(ctx, h.cfg))
+	s.AddTool(screenshots.SimilarTool(), screenshots.SimilarHandler(ctx, h.cfg))
+	s.AddTool(screenshots.ContactSheetTool(), screenshots.ContactSheetHandler(ctx, h.cfg))
+	s.AddTool(screenshots.NextTool(), screenshots.NextHandler(ctx, h.cfg))
+	s.AddTool(screenshots.StatusTool(), screenshots.StatusHandler(ctx, h.cfg))
+	s.AddTool(screenshots.SearchTextTool(), screenshots.SearchTextHandler(ctx, h.cfg))
+	// OCR is optional: it needs tesseract installed
+	if engine, err := ocr.NewTesseract(h.cfg.OCR.TesseractPath, h.cfg.OCR.Language); err != nil {
+		utils.Logger(ctx).Info("ocr_screenshot disabled", zap.Error(err))
+	} else {
+		s.AddTool(screenshots.OCRTool(), screenshots.OCRHandler(ctx, h.cfg, engine))
+	}
+	s.AddTool(discoveries.IsDiscoveredTool(), discoveries.IsDiscoveredHandler(ctx, h.cfg))
+	s.AddTool(spoilers.LevelTool(), spoilers.LevelHandler(ctx, h.cfg))
+	s.AddTool(backup.BackupTool(), backup.BackupHandler(ctx, h.cfg, h.store))
+	s.AddTool(backup.RestoreTool(), backup.RestoreHandler(ctx, h.cfg, h.store))
+	s.AddTool(backup.SyncTool(), backup.SyncHandler(ctx, h.cfg, h.store))
 }
 
 func (h *Handler) RegisterResources(ctx context.Context, s *server.MCPServer) error {
-	if err := rules.RegisterSpoilerRules(ctx, s); err != nil {
+	if err := rules.RegisterSpoilerRules(ctx, s, h.cfg); err != nil {
+		return err
+	}
+
+	if err := noteResources.RegisterNoteTemplates(ctx, s, h.cfg.ObsidianVaultPath); err != nil {
+		return err
+	}
+	if err := discoveryResources.RegisterKnown(ctx, s, h.cfg.ObsidianVaultPath); err != nil {
+		return err
+	}
+	h.subscriptions = subscriptions.New(s)
+	h.hooks.AddOnRegisterSession(h.subscriptions.Track)
+	h.hooks.AddOnUnregisterSession(h.subscriptions.Forget)
+	go noteResources.NewWatcher(h.cfg.ObsidianVaultPath, h.subscriptions).Watch(ctx, noteResources.DefaultWatchInterval)
+
+	if err := screenshotResources.RegisterTimeline(ctx, s, h.cfg.ObsidianVaultPath); err != nil {
 		return err
 	}
 
-	if err := files.RegisterVault(ctx, s, h.cfg.ObsidianVaultPath); err != nil {
+	if err := screenshotResources.RegisterScreenshotTemplate(ctx, s, h.cfg); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (h *Handler) RegisterPrompts(ctx context.Context, s *server.MCPServer) error {
+	return prompts.RegisterPrompts(ctx, s, h.cfg)
+}
+
+const (
+	// streamableHTTPPath is the endpoint of the streamable HTTP transport, mcp-go's default
+	streamableHTTPPath = "/mcp"
+	// shutdownTimeout is how long in-flight requests get to finish once an HTTP server is told to stop
+	shutdownTimeout = 10 * time.Second
+)
+
+// ServeStdio serves s over stdin and stdout until ctx is done or stdin is closed.
+// RegisterResources must be called first: resources/subscribe requests are answered by its subscription set.
+func (h *Handler) ServeStdio(ctx context.Context, s *server.MCPServer) error {
+	stdin, stdout := h.subscriptions.Stdio(os.Stdin, os.Stdout)
+	return server.NewStdioServer(s).Listen(ctx, stdin, stdout)
+}
+
+// ServeSSE serves s to any number of clients over the SSE transport at addr until ctx is done.
+// Clients connect to /sse with one of the bearer tokens in the secrets dir. RegisterResources must be called first, as for ServeStdio.
+func (h *Handler) ServeSSE(ctx context.Context, s *server.MCPServer, addr string) error {
+	srv := &http.Server{Addr: addr}
+	sse := server.NewSSEServer(s, server.WithHTTPServer(srv))
+	handler, err := h.authenticated(ctx, h.subscriptions.SSE(sse))
+	if err != nil {
+		return err
+	}
+	srv.Handler = handler
+	return serveHTTP(ctx, srv, sse.Shutdown)
+}
+
+// ServeStreamableHTTP serves s to any number of clients over the streamable HTTP transport at addr until ctx is done.
+// Clients connect to /mcp with one of the bearer tokens in the secrets dir. RegisterResources must be called first, as for ServeStdio.
+func (h *Handler) ServeStreamableHTTP(ctx context.Context, s *server.MCPServer, addr string) error {
+	streamable := server.NewStreamableHTTPServer(s)
+	handler, err := h.authenticated(ctx, h.subscriptions.StreamableHTTP(streamable))
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(streamableHTTPPath, handler)
+	srv := &http.Server{Addr: addr, Handler: mux}
+	return serveHTTP(ctx, srv, srv.Shutdown)
+}
+
+// authenticated wraps next so that only requests with one of the tokens in the secrets dir reach it
+func (h *Handler) authenticated(ctx context.Context, next http.Handler) (http.Handler, error) {
+	secretsDir, err := h.cfg.SecretsDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the secrets dir: %w", err)
+	}
+	verifier, err := auth.NewVerifier(secretsDir)
+	if err != nil {
+		return nil, err
+	}
+	if count, _ := verifier.Count(); count == 0 {
+		utils.Logger(ctx).Warn("No tokens yet, every request will be rejected. Create one with: blueprince-tools token create",
+			zap.String("tokens", auth.TokensPath(secretsDir)))
+	}
+	return auth.HTTP(ctx, verifier, next), nil
+}
+
+// serveHTTP runs srv until ctx is done, then stops it with shutdown. Requests still running after shutdownTimeout,
+// such as clients listening for notifications, are cut off.
+func serveHTTP(ctx context.Context, srv *http.Server, shutdown func(context.Context) error) error {
+	logger := utils.Logger(ctx)
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info("Listening for MCP connections", zap.String("addr", srv.Addr))
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := shutdown(shutdownCtx); err != nil {
+		logger.Warn("Server did not shut down cleanly, closing open connections", zap.Error(err))
+		srv.Close()
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
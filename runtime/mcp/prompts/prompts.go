@@ -0,0 +1,71 @@
+// Package prompts registers MCP prompts for the common Blue Prince note taking workflows.
+// Every prompt embeds the spoiler prevention rules and the notes it works from, so the client starts from the
+// player's own notes rather than its training data.
+package prompts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	noteResources "github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/rules"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// maxEmbeddedNotes caps the notes embedded in a single prompt to keep it within the client's context
+const maxEmbeddedNotes = 20
+
+// RegisterPrompts adds every workflow prompt to the server
+func RegisterPrompts(ctx context.Context, s *server.MCPServer, cfg *config.Config) error {
+	logger := utils.Logger(ctx)
+
+	s.AddPrompt(LogRoomPrompt(), LogRoomHandler(ctx, cfg))
+	s.AddPrompt(ProcessScreenshotsPrompt(), ProcessScreenshotsHandler(ctx, cfg))
+	s.AddPrompt(SessionRecapPrompt(), SessionRecapHandler(ctx, cfg))
+	s.AddPrompt(PuzzleBrainstormPrompt(), PuzzleBrainstormHandler(ctx, cfg))
+
+	logger.Info("Registered prompts", zap.Strings("prompts", []string{"log_room", "process_screenshots", "session_recap", "puzzle_brainstorm"}))
+	return nil
+}
+
+// rulesMessage embeds the spoiler prevention rules at the player's spoiler level
+func rulesMessage(cfg *config.Config) (mcp.PromptMessage, error) {
+	text, err := rules.SpoilerRules(cfg)
+	if err != nil {
+		return mcp.PromptMessage{}, err
+	}
+	return mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+		URI:      rules.SpoilerRulesURI,
+		MIMEType: "text/markdown; charset=utf-8",
+		Text:     text,
+	})), nil
+}
+
+// noteMessages embeds each note as its note:// resource
+func noteMessages(embedded []*notes.Note) []mcp.PromptMessage {
+	messages := make([]mcp.PromptMessage, len(embedded))
+	for i, note := range embedded {
+		messages[i] = mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      noteResources.NoteURI(note.Path),
+			MIMEType: "text/markdown; charset=utf-8",
+			Text:     note.Content,
+		}))
+	}
+	return messages
+}
+
+// buildMessages puts the rules first, then the notes, then the instructions
+func buildMessages(cfg *config.Config, embedded []*notes.Note, instructions string) ([]mcp.PromptMessage, error) {
+	rulesMsg, err := rulesMessage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	messages := append([]mcp.PromptMessage{rulesMsg}, noteMessages(embedded)...)
+	return append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(strings.TrimSpace(instructions)))), nil
+}
@@ -0,0 +1,119 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+func PuzzleBrainstormPrompt() mcp.Prompt {
+	return mcp.NewPrompt("puzzle_brainstorm",
+		mcp.WithPromptDescription("Brainstorm about a puzzle using only the player's own notes: the puzzle note and the notes that share its tags"),
+		mcp.WithArgument("puzzle_note",
+			mcp.ArgumentDescription("The puzzle's note, as a path relative to the notes dir (e.g. puzzles/parlor_boxes.md) or a note:// URI"),
+			mcp.RequiredArgument(),
+		),
+	)
+}
+
+// PuzzleBrainstormHandler creates a handler for the puzzle_brainstorm prompt.
+// The puzzle note is embedded first, then the notes sharing the most tags with it.
+func PuzzleBrainstormHandler(ctx context.Context, cfg *config.Config) server.PromptHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		notePath, err := puzzleNotePath(request.Params.Arguments["puzzle_note"])
+		if err != nil {
+			return nil, err
+		}
+
+		all, err := notes.LoadAll(cfg.ObsidianVaultPath)
+		if err != nil {
+			logger.Error("Failed to load notes", zap.Error(err))
+			return nil, fmt.Errorf("failed to load notes: %w", err)
+		}
+		index := slices.IndexFunc(all, func(n *notes.Note) bool { return n.Path == notePath })
+		if index == -1 {
+			return nil, fmt.Errorf("note not found: '%s'", notePath)
+		}
+		puzzle := all[index]
+		embedded := append([]*notes.Note{puzzle}, relatedNotes(all, puzzle, maxEmbeddedNotes-1)...)
+
+		title := puzzle.Metadata.Title
+		if title == "" {
+			title = notePath
+		}
+		instructions := fmt.Sprintf(`
+Help me think through the puzzle in my note "%s". Follow the spoiler prevention rules above.
+
+The first note above is the puzzle; the others are my notes that share its tags.
+- Work ONLY from what my notes say. Do NOT use what you know about Blue Prince and NEVER give me the solution.
+- Point out details from my notes that might be connected, and ask me questions that help me reason about them.
+- If my notes don't have enough to go on, say so and suggest where in the house I could look again, based only on what I wrote.
+`, title)
+
+		messages, err := buildMessages(cfg, embedded, instructions)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewGetPromptResult(fmt.Sprintf("Brainstorm about %s", title), messages), nil
+	}
+}
+
+// puzzleNotePath turns a note path or note:// URI into a path relative to the notes dir
+func puzzleNotePath(value string) (string, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "note://")
+	if value == "" {
+		return "", fmt.Errorf("puzzle_note is required")
+	}
+	cleanPath, err := utils.ValidatePath(value)
+	if err != nil {
+		return "", err
+	}
+	notePath := filepath.ToSlash(cleanPath)
+	if !strings.HasSuffix(notePath, ".md") {
+		notePath += ".md"
+	}
+	return notePath, nil
+}
+
+// relatedNotes returns up to limit notes sharing tags with note, most shared tags first.
+// Category names are left out since nearly every note is tagged with its own.
+func relatedNotes(all []*notes.Note, note *notes.Note, limit int) []*notes.Note {
+	tags := make(map[string]bool)
+	for _, tag := range note.Metadata.Tags {
+		if !slices.Contains(notes.Categories, tag) {
+			tags[utils.Slugify(tag)] = true
+		}
+	}
+
+	shared := make(map[*notes.Note]int)
+	var related []*notes.Note
+	for _, other := range all {
+		if other == note {
+			continue
+		}
+		for _, tag := range other.Metadata.Tags {
+			if tags[utils.Slugify(tag)] {
+				shared[other]++
+			}
+		}
+		if shared[other] > 0 {
+			related = append(related, other)
+		}
+	}
+
+	sort.SliceStable(related, func(i, j int) bool { return shared[related[i]] > shared[related[j]] })
+	return related[:min(len(related), limit)]
+}
@@ -0,0 +1,88 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+func SessionRecapPrompt() mcp.Prompt {
+	return mcp.NewPrompt("session_recap",
+		mcp.WithPromptDescription("Recap a play session from the notes written and screenshots taken since a given time"),
+		mcp.WithArgument("since",
+			mcp.ArgumentDescription("Start of the session: a date (YYYY-MM-DD, local time) or an RFC3339 timestamp"),
+			mcp.RequiredArgument(),
+		),
+	)
+}
+
+// SessionRecapHandler creates a handler for the session_recap prompt.
+// The notes updated since the given time are embedded, most recent first, along with the screenshot timeline.
+func SessionRecapHandler(ctx context.Context, cfg *config.Config) server.PromptHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		raw := strings.TrimSpace(request.Params.Arguments["since"])
+		if raw == "" {
+			return nil, fmt.Errorf("since is required")
+		}
+		since, err := utils.ParseTimeBound(raw, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since: %w", err)
+		}
+
+		all, err := notes.LoadAll(cfg.ObsidianVaultPath)
+		if err != nil {
+			logger.Error("Failed to load notes", zap.Error(err))
+			return nil, fmt.Errorf("failed to load notes: %w", err)
+		}
+		var recent []*notes.Note
+		for _, note := range all {
+			if !note.Updated().Before(since) {
+				recent = append(recent, note)
+			}
+		}
+		sort.SliceStable(recent, func(i, j int) bool { return recent[i].Updated().After(recent[j].Updated()) })
+		omitted := max(0, len(recent)-maxEmbeddedNotes)
+		recent = recent[:min(len(recent), maxEmbeddedNotes)]
+
+		timeline, err := screenshots.LoadTimeline(cfg.ObsidianVaultPath, since, time.Time{})
+		if err != nil {
+			logger.Warn("Failed to load the screenshot timeline", zap.Error(err))
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, `
+Recap my Blue Prince session since %s. Follow the spoiler prevention rules above.
+
+Summarize ONLY what my notes above say: the rooms I visited, what I found, and the open questions I wrote down myself.
+Group it by room or subject. Do NOT add theories, hints, connections I haven't made, or anything from outside my notes.
+`, since.Local().Format("Monday, January 2 2006 15:04"))
+		if len(recent) == 0 {
+			sb.WriteString("\nI haven't written or updated any notes in this period.\n")
+		}
+		if omitted > 0 {
+			fmt.Fprintf(&sb, "\n%d older note(s) from this period were left out. Read them with read_note if needed.\n", omitted)
+		}
+		if len(timeline) > 0 {
+			fmt.Fprintf(&sb, "\nScreenshots I took in this period:\n%s", screenshots.FormatTimeline(timeline))
+		}
+
+		messages, err := buildMessages(cfg, recent, sb.String())
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewGetPromptResult(fmt.Sprintf("Recap of the session since %s", raw), messages), nil
+	}
+}
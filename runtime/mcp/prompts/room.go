@@ -0,0 +1,88 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+func LogRoomPrompt() mcp.Prompt {
+	return mcp.NewPrompt("log_room",
+		mcp.WithPromptDescription("Log what the player saw in a room, adding to the room's existing notes instead of duplicating them"),
+		mcp.WithArgument("room_name",
+			mcp.ArgumentDescription("Name of the room as the player knows it, e.g. Nook"),
+			mcp.RequiredArgument(),
+		),
+	)
+}
+
+// LogRoomHandler creates a handler for the log_room prompt. The notes already written about the room are embedded.
+func LogRoomHandler(ctx context.Context, cfg *config.Config) server.PromptHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		roomName := strings.TrimSpace(request.Params.Arguments["room_name"])
+		if roomName == "" {
+			return nil, fmt.Errorf("room_name is required")
+		}
+
+		all, err := notes.LoadAll(cfg.ObsidianVaultPath)
+		if err != nil {
+			logger.Error("Failed to load notes", zap.Error(err))
+			return nil, fmt.Errorf("failed to load notes: %w", err)
+		}
+		existing := roomNotes(all, roomName)
+
+		instructions := fmt.Sprintf(`
+I'm in the %[1]s in Blue Prince and want to log what I see. Follow the spoiler prevention rules above.
+
+1. Ask me what I observed in the %[1]s, unless I already said. Do NOT suggest what to look for.
+2. If one of my notes above already covers the %[1]s, add my new observations to it with update_note. Otherwise call create_note with category "rooms" and a path like "rooms/%[2]s.md".
+3. Record ONLY what I tell you, in my own words. No theories, hints, or questions to investigate.
+`, roomName, utils.Slugify(roomName))
+		if len(existing) == 0 {
+			instructions += fmt.Sprintf("\nI have no notes about the %s yet.", roomName)
+		}
+
+		messages, err := buildMessages(cfg, existing, instructions)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewGetPromptResult(fmt.Sprintf("Log the %s", roomName), messages), nil
+	}
+}
+
+// roomNotes returns the notes about a room: room notes whose path or title names it, and any note tagged with it
+func roomNotes(all []*notes.Note, roomName string) []*notes.Note {
+	slug := utils.Slugify(roomName)
+	lowerName := strings.ToLower(roomName)
+
+	var matches []*notes.Note
+	for _, note := range all {
+		inRooms := note.Metadata.Category == "rooms" || strings.HasPrefix(note.Path, "rooms/")
+		namesRoom := strings.Contains(note.Path, slug) || strings.Contains(strings.ToLower(note.Metadata.Title), lowerName)
+		tagged := false
+		for _, tag := range note.Metadata.Tags {
+			if utils.Slugify(tag) == slug {
+				tagged = true
+				break
+			}
+		}
+
+		if (inRooms && namesRoom) || tagged {
+			matches = append(matches, note)
+			if len(matches) == maxEmbeddedNotes {
+				break
+			}
+		}
+	}
+	return matches
+}
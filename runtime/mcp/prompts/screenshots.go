@@ -0,0 +1,70 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// defaultBatchSize is how many screenshots process_screenshots works through when no limit is given
+const defaultBatchSize = 10
+
+func ProcessScreenshotsPrompt() mcp.Prompt {
+	return mcp.NewPrompt("process_screenshots",
+		mcp.WithPromptDescription("Work through the queue of screenshots that still need a note: analyze each one and write down what the player saw"),
+		mcp.WithArgument("limit",
+			mcp.ArgumentDescription(fmt.Sprintf("Max number of screenshots to process in this batch. Defaults to %d.", defaultBatchSize)),
+		),
+	)
+}
+
+// ProcessScreenshotsHandler creates a handler for the process_screenshots prompt. The current queue size is included.
+func ProcessScreenshotsHandler(ctx context.Context, cfg *config.Config) server.PromptHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		limit := defaultBatchSize
+		if raw := strings.TrimSpace(request.Params.Arguments["limit"]); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 {
+				return nil, fmt.Errorf("limit must be a positive integer, got '%s'", raw)
+			}
+			limit = parsed
+		}
+
+		_, m, err := screenshots.NextInQueue(cfg.ObsidianVaultPath)
+		if err != nil {
+			logger.Error("Failed to read the screenshot queue", zap.Error(err))
+			return nil, fmt.Errorf("failed to read the screenshot queue: %w", err)
+		}
+
+		instructions := fmt.Sprintf(`
+Help me turn my Blue Prince screenshots into notes. Follow the spoiler prevention rules above.
+%d screenshot(s) are waiting in the queue. %s
+
+Repeat for up to %d screenshot(s), or until the queue is empty:
+1. Call next_screenshot_to_analyze to get the next file_name.
+2. Call find_similar_screenshots with the file_name. If it is a near-duplicate of a screenshot that already has a note, mark it "skipped" with set_screenshot_status and move on.
+3. Call analyze_screenshot with the file_name and describe ONLY what is visible. Use crop_screenshot (or ocr_screenshot, if available) for small text and transcribe it exactly.
+4. Call create_note, or update_note if one of my notes already covers the same subject, with the file_name listed under metadata.screenshots.
+5. If the screenshot shows nothing worth a note, mark it "skipped" with set_screenshot_status.
+
+NEVER add hints, theories, or anything I could not see in the screenshot. At the end, list the notes you created or updated.
+`, len(m.Queue()), screenshots.FormatStatusCounts(m.StatusCounts()), limit)
+
+		messages, err := buildMessages(cfg, nil, instructions)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewGetPromptResult("Process a batch of screenshots", messages), nil
+	}
+}
@@ -0,0 +1,62 @@
+package discoveries
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/discoveries"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// KnownURI is the URI of the resource listing everything the player has documented
+const KnownURI = "discoveries://known"
+
+// knownList is the JSON served at KnownURI
+type knownList struct {
+	Count       int                      `json:"count"`
+	Discoveries []*discoveries.Discovery `json:"discoveries"`
+}
+
+// RegisterKnown adds a resource listing the discovery registry: every room, person, item and term named in the notes.
+// The registry is rebuilt from the notes on each read.
+func RegisterKnown(ctx context.Context, s *server.MCPServer, vaultPath string) error {
+	logger := utils.Logger(ctx)
+
+	knownResource := mcp.NewResource(
+		KnownURI,
+		"Known Discoveries",
+		mcp.WithResourceDescription("Machine-readable list of the rooms, people, items and terms the player has documented, with when and where each was first seen. External information may ONLY be shared about these"),
+		mcp.WithMIMEType("application/json"),
+	)
+
+	knownHandler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Info("Loading known discoveries", zap.String("uri", req.Params.URI))
+		registry, err := discoveries.Load(vaultPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load discoveries: %w", err)
+		}
+
+		all := registry.All()
+		data, err := json.MarshalIndent(knownList{Count: len(all), Discoveries: all}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode discoveries: %w", err)
+		}
+		return []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      req.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+
+	s.AddResource(knownResource, knownHandler)
+	logger.Info("Registered known discoveries resource", zap.String("uri", KnownURI))
+
+	return nil
+}
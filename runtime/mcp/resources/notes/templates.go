@@ -0,0 +1,218 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const (
+	noteTemplate     = "note://{category}/{slug}"
+	metaTemplate     = "note-meta://{category}/{slug}"
+	categoryTemplate = "category://{name}"
+
+	// noteExt is the extension of note files. Slugs are note file names without it.
+	noteExt = ".md"
+)
+
+// NoteURI returns the note:// URI of the note at notePath, relative to the notes dir (e.g. rooms/nook.md)
+func NoteURI(notePath string) string {
+	return "note://" + strings.TrimSuffix(filepath.ToSlash(notePath), noteExt)
+}
+
+// MetaURI returns the note-meta:// URI of the note at notePath, relative to the notes dir
+func MetaURI(notePath string) string {
+	return "note-meta://" + strings.TrimSuffix(filepath.ToSlash(notePath), noteExt)
+}
+
+// CategoryURI returns the category:// URI listing the notes of category
+func CategoryURI(category string) string {
+	return "category://" + category
+}
+
+// RegisterNoteTemplates adds resource templates serving notes on demand: a note's markdown, its frontmatter as JSON
+// and a listing of each category. Paths are resolved per request, so notes created after startup are served too.
+func RegisterNoteTemplates(ctx context.Context, s *server.MCPServer, vaultPath string) error {
+	logger := utils.Logger(ctx)
+
+	noteHandler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Info("Loading note resource", zap.String("uri", req.Params.URI))
+		content, _, err := readNote(vaultPath, req.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      req.Params.URI,
+				MIMEType: "text/markdown; charset=utf-8",
+				Text:     content,
+			},
+		}, nil
+	}
+
+	metaHandler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Info("Loading note metadata resource", zap.String("uri", req.Params.URI))
+		content, notePath, err := readNote(vaultPath, req.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		metadata, _, err := notes.ParseContent(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse note '%s': %w", notePath, err)
+		}
+		data, err := json.MarshalIndent(metadata, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode metadata of note '%s': %w", notePath, err)
+		}
+		return []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      req.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+
+	categoryHandler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Info("Loading category resource", zap.String("uri", req.Params.URI))
+		name, err := utils.TemplateArg(req.Params.Arguments, "name")
+		if err != nil {
+			return nil, err
+		}
+		listing, err := listCategory(vaultPath, name)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      req.Params.URI,
+				MIMEType: "text/markdown; charset=utf-8",
+				Text:     listing,
+			},
+		}, nil
+	}
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		noteTemplate,
+		"Note",
+		mcp.WithTemplateDescription("A note in the vault's ./notes dir as markdown with YAML frontmatter, by category dir and file name without .md, e.g. note://rooms/nook_tiger_paintings"),
+		mcp.WithTemplateMIMEType("text/markdown; charset=utf-8"),
+	), noteHandler)
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		metaTemplate,
+		"Note Metadata",
+		mcp.WithTemplateDescription("Only the frontmatter of a note (title, category, tags, confidence, status, screenshots...) as JSON"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), metaHandler)
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		categoryTemplate,
+		"Note Category",
+		mcp.WithTemplateDescription(fmt.Sprintf("A listing of the notes in a category with their title, status and note:// URI. Categories: %v", notes.Categories)),
+		mcp.WithTemplateMIMEType("text/markdown; charset=utf-8"),
+	), categoryHandler)
+	logger.Info("Registered note resource templates",
+		zap.Strings("uriTemplates", []string{noteTemplate, metaTemplate, categoryTemplate}))
+
+	return nil
+}
+
+// readNote resolves the category and slug template variables to a note and returns its content and its path
+// relative to the notes dir
+func readNote(vaultPath string, args map[string]any) (string, string, error) {
+	category, err := utils.TemplateArg(args, "category")
+	if err != nil {
+		return "", "", err
+	}
+	slug, err := utils.TemplateArg(args, "slug")
+	if err != nil {
+		return "", "", err
+	}
+	if category == "" || slug == "" {
+		return "", "", fmt.Errorf("category and slug are required")
+	}
+
+	cleanPath, err := utils.ValidatePath(filepath.Join(category, strings.TrimSuffix(slug, noteExt)+noteExt))
+	if err != nil {
+		return "", "", err
+	}
+	fullPath, err := utils.BuildSecurePath(vaultPath, vault.NOTES_DIR, cleanPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", fmt.Errorf("note not found: '%s'", filepath.ToSlash(cleanPath))
+		}
+		return "", "", fmt.Errorf("failed to read note '%s': %w", filepath.ToSlash(cleanPath), err)
+	}
+	return string(content), cleanPath, nil
+}
+
+// listCategory lists the notes directly in a category dir as markdown links to their note:// URIs
+func listCategory(vaultPath, category string) (string, error) {
+	if category == "" || strings.ContainsAny(category, `/\`) {
+		return "", fmt.Errorf("invalid category '%s'", category)
+	}
+	cleanPath, err := utils.ValidatePath(category)
+	if err != nil {
+		return "", err
+	}
+	dir, err := utils.BuildSecurePath(vaultPath, vault.NOTES_DIR, cleanPath)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("category not found: '%s'. Categories: %v", category, notes.Categories)
+		}
+		return "", fmt.Errorf("failed to list category '%s': %w", category, err)
+	}
+
+	var lines []string
+	for _, entry := range entries {
+		if entry.IsDir() || utils.ShouldSkipPath(entry.Name(), entry) || filepath.Ext(entry.Name()) != noteExt {
+			continue
+		}
+		notePath := filepath.Join(cleanPath, entry.Name())
+		title := strings.TrimSuffix(entry.Name(), noteExt)
+		var status string
+		// A note with broken frontmatter is still listed, by file name
+		if content, err := os.ReadFile(filepath.Join(dir, entry.Name())); err == nil {
+			if metadata, _, err := notes.ParseContent(string(content)); err == nil {
+				if metadata.Title != "" {
+					title = metadata.Title
+				}
+				status = metadata.Status
+			}
+		}
+
+		line := fmt.Sprintf("- [%s](%s)", title, NoteURI(notePath))
+		if status != "" {
+			line += fmt.Sprintf(" (%s)", status)
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	text := fmt.Sprintf("# %s\n\n", category)
+	if len(lines) == 0 {
+		return text + "No notes in this category.\n", nil
+	}
+	return text + strings.Join(lines, "\n") + "\n", nil
+}
@@ -0,0 +1,149 @@
+package notes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/discoveries"
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/subscriptions"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"go.uber.org/zap"
+)
+
+// DefaultWatchInterval is how often Watch rescans the notes dir for added, removed and changed notes
+const DefaultWatchInterval = 2 * time.Second
+
+// fileState is what a scan remembers of a note to tell whether it changed
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher tells subscribed clients when the note resources change on disk, whether through create_note or Obsidian.
+// Scan is not safe for concurrent use: call it from one goroutine, e.g. through Watch.
+type Watcher struct {
+	notesDir      string
+	subscriptions *subscriptions.Set
+	// notes are the notes seen by the last scan, by path relative to the notes dir
+	notes map[string]fileState
+}
+
+// NewWatcher returns a Watcher over the vault's notes dir
+func NewWatcher(vaultPath string, subs *subscriptions.Set) *Watcher {
+	return &Watcher{
+		notesDir:      filepath.Join(vaultPath, vault.NOTES_DIR),
+		subscriptions: subs,
+	}
+}
+
+// Watch rescans the notes dir every interval until ctx is done.
+// Subscribers of a changed note's note:// and note-meta:// URIs are sent notifications/resources/updated,
+// as are subscribers of the category:// listing when a note in it is added, removed or changed, and of discoveries://known.
+func (w *Watcher) Watch(ctx context.Context, interval time.Duration) {
+	logger := utils.Logger(ctx)
+	// The first scan only records the current state
+	if err := w.Scan(ctx); err != nil {
+		logger.Warn("Failed to scan notes", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Scan(ctx); err != nil {
+				logger.Warn("Failed to rescan notes", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Scan walks the notes dir once and notifies subscribers of every note that was added, removed or changed since the last scan
+func (w *Watcher) Scan(ctx context.Context) error {
+	logger := utils.Logger(ctx)
+
+	seen := make(map[string]fileState)
+	err := filepath.WalkDir(w.notesDir, func(fullFilePath string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if fullFilePath == w.notesDir && os.IsNotExist(walkErr) {
+				// No notes yet
+				return filepath.SkipDir
+			}
+			logger.Error("Error accessing path during note scan",
+				zap.String("path", fullFilePath),
+				zap.Error(walkErr),
+			)
+			// Skip problematic files, only error on dir errors to prevent further attempts
+			if d == nil || !d.IsDir() {
+				return nil
+			}
+			return walkErr
+		}
+
+		// Skip unwanted paths (.obsidian, hidden files, etc.)
+		if utils.ShouldSkipPath(fullFilePath, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || filepath.Ext(d.Name()) != noteExt {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(w.notesDir, fullFilePath)
+		if err != nil {
+			return nil // Skip this file
+		}
+		info, err := d.Info()
+		if err != nil {
+			// The note was removed mid scan
+			return nil
+		}
+		seen[filepath.ToSlash(relativePath)] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error walking directory %s for notes: %w", w.notesDir, err)
+	}
+
+	if w.notes != nil {
+		changed := false
+		for notePath, state := range seen {
+			previous, existed := w.notes[notePath]
+			if !existed || previous != state {
+				w.notify(notePath)
+				changed = true
+			}
+		}
+		for notePath := range w.notes {
+			if _, ok := seen[notePath]; !ok {
+				w.notify(notePath)
+				changed = true
+			}
+		}
+		// The discovery registry is derived from every note, so it is notified once per scan
+		if changed {
+			w.subscriptions.NotifyUpdated(discoveries.KnownURI)
+		}
+	}
+	w.notes = seen
+	return nil
+}
+
+// notify sends notifications/resources/updated for every resource showing the note at notePath
+func (w *Watcher) notify(notePath string) {
+	w.subscriptions.NotifyUpdated(NoteURI(notePath))
+	w.subscriptions.NotifyUpdated(MetaURI(notePath))
+	if category, _, ok := strings.Cut(notePath, "/"); ok {
+		w.subscriptions.NotifyUpdated(CategoryURI(category))
+	}
+}
@@ -1,17 +1,42 @@
 package rules
 
-const SpoilerRules = `# Blue Prince MCP Server - SPOILER PREVENTION RULES
+import "github.com/myungbeans/blueprince-mcp/runtime/models/spoilers"
+
+// spoilerRulesTemplate is rendered with a rulesData by Render. The general rules are those of the filtered level;
+// the level sections above them say where the player's chosen levels loosen or tighten them.
+const spoilerRulesTemplate = `# Blue Prince MCP Server - SPOILER PREVENTION RULES
 
 ## CRITICAL: SPOILER-FREE SYSTEM
 
 This MCP server is designed to preserve the user's Blue Prince gameplay experience. **You MUST follow these rules strictly.**
 
+## SPOILER LEVEL: {{upper .Level}}
+{{levelRules .Level}}
+{{- if .Overrides}}
+
+### Per-category levels
+Topics from these note categories use their own level instead. Apply the level of the category the topic belongs to, e.g. the category of the note it comes from:
+{{range .Overrides}}
+#### {{.Category}}: {{.Level}}
+{{levelRules .Level}}
+{{end}}
+{{- end}}
+
 ## ABSOLUTE REQUIREMENTS
+{{if eq .Level "strict"}}
+### 1. INFORMATION SOURCES - NOTES ONLY
+- **ONLY SOURCE**: Use information from the user's notes accessed through this MCP server, and nothing else
+- **NO EXTERNAL SOURCES**: Do NOT use your training data, wikis, guides or web_search/web_fetch for Blue Prince topics, even about things the user has already documented
+- **NO NEW DISCOVERIES**: If the notes don't answer a question, say so: "I can only help with information from your notes."
 
+### 2. EXTERNAL INFORMATION
+External information is off limits at this level, unless a per-category level above allows it. If the user asks for it, remind them of their spoiler level and that it can be changed with set_spoiler_level.
+{{else}}
 ### 1. INFORMATION SOURCES - FILTERED ACCESS ALLOWED
 - **PRIMARY SOURCE**: Always use information from the user's notes accessed through this MCP server
 - **EXTERNAL SOURCES**: You MAY access your training data, wikis, guides, and general knowledge about Blue Prince, BUT with strict filtering (see Section 2)
 - **CRITICAL CONSTRAINT**: Before any web_search or web_fetch call, MCP CLIENT must check if the query relates to proper nouns, locations, or entities found in existing Blue Prince notes. If ANY overlap exists, FILTER the search web_search/web_fetch results to include ONLY information that is also included in a user's notes.
+- **DISCOVERY CHECK**: Call is_discovered with each proper noun, location or entity (or read discoveries://known) to find out whether the user has documented it. Anything that is not discovered is off limits.
 - **FILTERING RULE**: Any external information MUST be filtered to show ONLY what the user has already documented in their notes
 - **NO NEW DISCOVERIES**: External sources can only be used to provide context for things the user has already found
 
@@ -28,7 +53,7 @@ When user asks about external information on Blue Prince topics:
 2. Warn: "This search might contain spoilers about [specific topic]"  
 3. Ask: "Do you want me to search anyway, or would you prefer to discover this through gameplay?"
 4. Only search with explicit "yes" confirmation
-
+{{end}}
 
 ### 3. FORBIDDEN ACTIONS
 - **NEVER** provide solutions to puzzles the user hasn't solved
@@ -45,6 +70,8 @@ When creating or updating notes:
 - Use the user's exact words and observations as the primary content
 - External information may be used to provide internal context or clarification for user discoveries, BUT if there is any potential for spoilers, clearly mark it in the note.
 - Clearly distinguish between user observations and external context, making sure that external context does not reach into spoiler territory (i.e. the context or clarification is used for better wording and formatting and does not extend beyond what the user has noted in this update or in other notes).
+- Tag where content came from. Untagged content is the user's own words. Put text read off a screenshot in a "> [!screenshot]" callout and any external context in a "> [!external]" callout, or in a fenced block with the info string "source: screenshot" or "source: external". The server rejects malformed blocks and keeps a count of each source in the note's provenance metadata.
+- Use read_note with view "strip_external" to see only what the user wrote or saw.
 - Preserve the user's discovery language and uncertainty
 - Always prioritize user experience over external knowledge
 
@@ -75,3 +102,15 @@ When external information is available:
 The goal is preserving discovery while allowing helpful context for what's already been found. Always err on the side of caution.
 
 **Remember: You are a spoiler-aware assistant that enhances discovered content without revealing undiscovered content.**`
+
+// levelRules spell out what each spoiler level allows, on top of the general rules
+var levelRules = map[string]string{
+	spoilers.LevelStrict: `- Use ONLY the user's notes. Training data, wikis, guides and web searches are off limits, even for documented content.
+- Sections 1 and 2 below are replaced by the notes-only rules, and the external information allowances of sections 5-7 do not apply.`,
+	spoilers.LevelFiltered: `- External information is allowed ONLY about what the user has already documented, filtered and with consent, as described in sections 1 and 2 below.`,
+	spoilers.LevelHints: `- Everything allowed at the filtered level.
+- When the user asks for help with a puzzle they have documented, you MAY give a gentle hint that points at something already in their notes. NEVER give the solution, and give one hint at a time.`,
+	spoilers.LevelFull: `- Everything allowed at the hints level.
+- You MAY fully explain mechanics, puzzles and story elements the user has documented, including puzzle solutions when the user asks for them. This overrides section 3 for documented content ONLY.
+- Undocumented content stays off limits: NEVER reveal rooms, items, characters or story the user has not written about.`,
+}
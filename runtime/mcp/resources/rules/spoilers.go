@@ -1,8 +1,14 @@
 package rules
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strings"
+	"text/template"
 
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/spoilers"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -10,12 +16,46 @@ import (
 	"go.uber.org/zap"
 )
 
+// SpoilerRulesURI is the URI of the spoiler prevention rules resource
+const SpoilerRulesURI = "rules://blue-prince/spoiler-protection"
+
+var spoilerRules = template.Must(template.New("spoiler-rules").Funcs(template.FuncMap{
+	"upper":      strings.ToUpper,
+	"levelRules": func(level string) string { return levelRules[level] },
+}).Parse(spoilerRulesTemplate))
+
+// rulesData is what the spoiler rules template is rendered with
+type rulesData struct {
+	Level     string
+	Overrides []spoilers.CategoryLevel
+}
+
+// Render returns the spoiler prevention rules for a policy
+func Render(policy spoilers.Policy) (string, error) {
+	var buf bytes.Buffer
+	data := rulesData{Level: policy.DefaultLevel(), Overrides: policy.Overrides()}
+	if err := spoilerRules.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render spoiler rules: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// SpoilerRules returns the spoiler prevention rules in force: the configured policy with the levels chosen through
+// set_spoiler_level applied on top
+func SpoilerRules(cfg *config.Config) (string, error) {
+	policy, err := spoilers.Effective(cfg.ObsidianVaultPath, cfg.Spoilers)
+	if err != nil {
+		return "", err
+	}
+	return Render(policy)
+}
+
 // RegisterSpoilerRules adds a special resource containing the spoiler prevention rules
-func RegisterSpoilerRules(ctx context.Context, s *server.MCPServer) error {
+func RegisterSpoilerRules(ctx context.Context, s *server.MCPServer, cfg *config.Config) error {
 	logger := utils.Logger(ctx)
 
 	rulesResource := mcp.NewResource(
-		"rules://blue-prince/spoiler-protection",
+		SpoilerRulesURI,
 		"Blue Prince Spoiler Protection Rules",
 		mcp.WithResourceDescription("CRITICAL rules for spoiler-free Blue Prince assistance that the assistant must follow at all times"),
 		mcp.WithMIMEType("text/markdown; charset=utf-8"),
@@ -23,18 +63,22 @@ func RegisterSpoilerRules(ctx context.Context, s *server.MCPServer) error {
 
 	rulesHandler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 		logger.Info("Loading spoiler prevention rules", zap.String("uri", req.Params.URI))
+		text, err := SpoilerRules(cfg)
+		if err != nil {
+			return nil, err
+		}
 		return []mcp.ResourceContents{
 			&mcp.TextResourceContents{
 				URI:      req.Params.URI,
 				MIMEType: "text/markdown; charset=utf-8",
-				Text:     SpoilerRules,
+				Text:     text,
 			},
 		}, nil
 	}
 
 	s.AddResource(rulesResource, rulesHandler)
 	logger.Info("Registered spoiler prevention rules resource",
-		zap.String("uri", "rules://blue-prince/spoiler-protection"))
+		zap.String("uri", SpoilerRulesURI))
 
 	return nil
 }
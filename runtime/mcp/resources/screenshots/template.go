@@ -0,0 +1,82 @@
+package screenshots
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const (
+	screenshotTemplate = "screenshot://{name}{?w,h,q}"
+	// defaultVariantQuality is the JPEG quality of resized variants when q is not given
+	defaultVariantQuality = 85
+)
+
+// RegisterScreenshotTemplate adds a resource template serving the vault's screenshots as blobs.
+// Without query params the screenshot is compressed to fit the configured image budget.
+// w and h cap the width and height in px and q sets the JPEG quality, for a variant made to order.
+func RegisterScreenshotTemplate(ctx context.Context, s *server.MCPServer, cfg *config.Config) error {
+	logger := utils.Logger(ctx)
+
+	template := mcp.NewResourceTemplate(
+		screenshotTemplate,
+		"Screenshot",
+		mcp.WithTemplateDescription(fmt.Sprintf("A screenshot in the vault's ./screenshots dir, by percent-encoded file name. Served as JPEG or PNG. Optional query params: w and h cap the size in px (default %d), q sets the JPEG quality 1-100 (default %d). Without any, the image is compressed to fit the configured image budget.", utils.MaxImageEdge, defaultVariantQuality)),
+	)
+
+	handler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Info("Loading screenshot resource", zap.String("uri", req.Params.URI))
+
+		name, err := utils.TemplateArg(req.Params.Arguments, "name")
+		if err != nil {
+			return nil, err
+		}
+		fullPath, err := screenshots.FilePath(cfg.ObsidianVaultPath, name)
+		if err != nil {
+			return nil, err
+		}
+
+		width, err := utils.TemplateIntArg(req.Params.Arguments, "w", utils.MaxImageEdge, 1, utils.MaxImageEdge)
+		if err != nil {
+			return nil, err
+		}
+		height, err := utils.TemplateIntArg(req.Params.Arguments, "h", utils.MaxImageEdge, 1, utils.MaxImageEdge)
+		if err != nil {
+			return nil, err
+		}
+		quality, err := utils.TemplateIntArg(req.Params.Arguments, "q", defaultVariantQuality, 1, 100)
+		if err != nil {
+			return nil, err
+		}
+
+		var img *utils.CompressedImageResult
+		if utils.HasTemplateArg(req.Params.Arguments, "w", "h", "q") {
+			img, err = utils.CompressImage(fullPath, width, height, quality)
+		} else {
+			img, err = utils.CompressImageToBudget(fullPath, cfg.ImageMaxBytes())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load screenshot '%s': %w", name, err)
+		}
+
+		return []mcp.ResourceContents{
+			&mcp.BlobResourceContents{
+				URI:      req.Params.URI,
+				MIMEType: img.MimeType,
+				Blob:     img.Data,
+			},
+		}, nil
+	}
+
+	s.AddResourceTemplate(template, handler)
+	logger.Info("Registered screenshot resource template", zap.String("uriTemplate", screenshotTemplate))
+
+	return nil
+}
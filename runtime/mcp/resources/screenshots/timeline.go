@@ -0,0 +1,55 @@
+package screenshots
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const timelineURI = "screenshots://timeline"
+
+// RegisterTimeline adds a resource listing every screenshot in the vault in the order it was captured
+func RegisterTimeline(ctx context.Context, s *server.MCPServer, vaultPath string) error {
+	logger := utils.Logger(ctx)
+
+	timelineResource := mcp.NewResource(
+		timelineURI,
+		"Screenshot Timeline",
+		mcp.WithResourceDescription("Every screenshot in the vault grouped by the day it was captured, oldest first. Capture times come from EXIF data or the screenshot tool's file name where available"),
+		mcp.WithMIMEType("text/markdown; charset=utf-8"),
+	)
+
+	timelineHandler := func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Info("Loading screenshot timeline", zap.String("uri", req.Params.URI))
+		timeline, err := screenshots.LoadTimeline(vaultPath, time.Time{}, time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load screenshot timeline: %w", err)
+		}
+
+		text := "# Screenshot Timeline\n\n"
+		if len(timeline) == 0 {
+			text += "No screenshots with a known capture time.\n"
+		} else {
+			text += screenshots.FormatTimeline(timeline)
+		}
+		return []mcp.ResourceContents{
+			&mcp.TextResourceContents{
+				URI:      req.Params.URI,
+				MIMEType: "text/markdown; charset=utf-8",
+				Text:     text,
+			},
+		}, nil
+	}
+
+	s.AddResource(timelineResource, timelineHandler)
+	logger.Info("Registered screenshot timeline resource", zap.String("uri", timelineURI))
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package subscriptions
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sessionIDHeader carries the session of a streamable HTTP request
+const sessionIDHeader = "Mcp-Session-Id"
+
+// StreamableHTTP wraps a streamable HTTP server so that subscription requests are answered here, with a JSON response
+// to the POST carrying them, and every other request is passed through to next.
+// Subscriptions belong to the session in the request's Mcp-Session-Id header. The session can only subscribe while its
+// client listens on a GET stream, and its subscriptions end when that stream closes or the client deletes the session.
+func (set *Set) StreamableHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(sessionIDHeader)
+		if r.Method == http.MethodDelete && sessionID != "" {
+			set.forget(sessionID)
+		}
+		body, ok := readPost(r)
+		if !ok || sessionID == "" {
+			// Requests without a session, such as initialize, are never subscription requests
+			next.ServeHTTP(w, r)
+			return
+		}
+		response := set.handle(sessionID, body)
+		if response == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(response)
+	})
+}
+
+// SSE wraps an SSE server so that subscription requests are answered here and every other request is passed through.
+// As with the SSE server's own responses, the POST is accepted and the response is sent on the client's event stream.
+// Subscriptions belong to the session in the message endpoint's sessionId query parameter.
+// A subscription whose response can't be sent is dropped again, as the client won't know about it.
+func (set *Set) SSE(sse *server.SSEServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.URL.Query().Get("sessionId")
+		body, ok := readPost(r)
+		if !ok || sessionID == "" {
+			// The SSE server rejects messages without a session
+			sse.ServeHTTP(w, r)
+			return
+		}
+		request, ok := parseRequest(body)
+		if !ok {
+			sse.ServeHTTP(w, r)
+			return
+		}
+		response := set.apply(sessionID, request)
+
+		if err := sse.SendEventToSession(sessionID, json.RawMessage(response)); err != nil {
+			if request.Method == methodSubscribe {
+				set.unsubscribe(sessionID, request.Params.URI)
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// readPost reads the body of a POST and puts it back for the next handler.
+// It reports false for other methods, or if the body can't be read, in which case the next handler deals with it.
+func readPost(r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		return nil, false
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err == nil
+}
@@ -0,0 +1,198 @@
+// Package subscriptions implements resources/subscribe, which the mcp-go server advertises but does not handle itself
+package subscriptions
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Method names of the subscription requests
+const (
+	methodSubscribe   = "resources/subscribe"
+	methodUnsubscribe = "resources/unsubscribe"
+)
+
+// StdioSession is the ID of the single session of a stdio server, as mcp-go names it
+const StdioSession = "stdio"
+
+// Set tracks the resource URIs each client session subscribed to.
+// Only sessions the server registered, i.e. with a stream open to notify them on, can subscribe:
+// Track and Forget must be added to the server's hooks.
+type Set struct {
+	s  *server.MCPServer
+	mu sync.RWMutex
+	// sessions maps the ID of each registered session to the URIs it subscribed to
+	sessions map[string]map[string]bool
+}
+
+// New returns an empty Set that notifies the clients of s
+func New(s *server.MCPServer) *Set {
+	return &Set{s: s, sessions: make(map[string]map[string]bool)}
+}
+
+// Subscribers returns the IDs of the sessions subscribed to uri
+func (set *Set) Subscribers(uri string) []string {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	var sessionIDs []string
+	for sessionID, uris := range set.sessions {
+		if uris[uri] {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+	return sessionIDs
+}
+
+// NotifyUpdated tells the sessions subscribed to uri that it changed.
+// A session the server no longer knows is forgotten, in case its end was missed.
+func (set *Set) NotifyUpdated(uri string) {
+	for _, sessionID := range set.Subscribers(uri) {
+		err := set.s.SendNotificationToSpecificClient(sessionID, string(mcp.MethodNotificationResourceUpdated), map[string]any{"uri": uri})
+		if errors.Is(err, server.ErrSessionNotFound) {
+			set.forget(sessionID)
+		}
+	}
+}
+
+// Track lets a session that started subscribe.
+// It has the signature of a server.OnRegisterSessionHookFunc so it can be added to the server's hooks.
+func (set *Set) Track(ctx context.Context, session server.ClientSession) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if _, ok := set.sessions[session.SessionID()]; !ok {
+		set.sessions[session.SessionID()] = make(map[string]bool)
+	}
+}
+
+// Forget drops the subscriptions of a session that ended.
+// It has the signature of a server.OnUnregisterSessionHookFunc so it can be added to the server's hooks.
+// A streamable HTTP session ends here when its client closes the stream it listens on.
+func (set *Set) Forget(ctx context.Context, session server.ClientSession) {
+	set.forget(session.SessionID())
+}
+
+func (set *Set) forget(sessionID string) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	delete(set.sessions, sessionID)
+}
+
+// unsubscribe drops a single subscription of a session
+func (set *Set) unsubscribe(sessionID, uri string) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	delete(set.sessions[sessionID], uri)
+}
+
+// Stdio wraps the stdin and stdout of a stdio server so that subscription requests are answered here
+// and every other message is passed through to the MCP server.
+func (set *Set) Stdio(stdin io.Reader, stdout io.Writer) (io.Reader, io.Writer) {
+	out := &lineWriter{w: stdout}
+	pr, pw := io.Pipe()
+
+	go func() {
+		reader := bufio.NewReader(stdin)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if response := set.handle(StdioSession, line); response != nil {
+					out.Write(append(response, '\n'))
+				} else if _, err := pw.Write(line); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					pw.Close()
+				} else {
+					pw.CloseWithError(err)
+				}
+				return
+			}
+		}
+	}()
+	return pr, out
+}
+
+// request is a resources/subscribe or resources/unsubscribe request
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+// parseRequest reads a subscription request. It reports false for any other message.
+func parseRequest(line []byte) (*request, bool) {
+	var r request
+	if err := json.Unmarshal(line, &r); err != nil || len(r.ID) == 0 {
+		return nil, false
+	}
+	if r.Method != methodSubscribe && r.Method != methodUnsubscribe {
+		return nil, false
+	}
+	return &r, true
+}
+
+// handle answers a subscription request of the given session. It returns nil for any other message.
+func (set *Set) handle(sessionID string, line []byte) []byte {
+	r, ok := parseRequest(line)
+	if !ok {
+		return nil
+	}
+	return set.apply(sessionID, r)
+}
+
+// apply records a subscription request of the given session and returns the response to it
+func (set *Set) apply(sessionID string, r *request) []byte {
+	if r.Params.URI == "" {
+		return respond(r.ID, "error", map[string]any{"code": mcp.INVALID_PARAMS, "message": "uri is required"})
+	}
+
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	uris, ok := set.sessions[sessionID]
+	if !ok {
+		return respond(r.ID, "error", map[string]any{
+			"code":    mcp.INVALID_REQUEST,
+			"message": fmt.Sprintf("session '%s' has no open stream to send resource updates on", sessionID),
+		})
+	}
+	if r.Method == methodSubscribe {
+		uris[r.Params.URI] = true
+	} else {
+		delete(uris, r.Params.URI)
+	}
+	return respond(r.ID, "result", map[string]any{})
+}
+
+func respond(id json.RawMessage, key string, value any) []byte {
+	response, _ := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		key:       value,
+	})
+	return response
+}
+
+// lineWriter serializes writes so responses written here don't interleave with the server's own.
+// The stdio server writes each message with a single Write call.
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
@@ -0,0 +1,162 @@
+package subscriptions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testSession is a client session whose notifications can be read back
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newTestSession(id string) *testSession {
+	return &testSession{id: id, notifications: make(chan mcp.JSONRPCNotification, 10)}
+}
+
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) SessionID() string                                   { return s.id }
+
+// received returns the URIs of the update notifications sent to the session so far
+func (s *testSession) received() []string {
+	var uris []string
+	for {
+		select {
+		case n := <-s.notifications:
+			uris = append(uris, n.Params.AdditionalFields["uri"].(string))
+		default:
+			return uris
+		}
+	}
+}
+
+// newTestSet returns a Set over a server whose hooks track its sessions, as the runtime Handler sets them up
+func newTestSet() (*server.MCPServer, *Set) {
+	hooks := &server.Hooks{}
+	s := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(true, false), server.WithHooks(hooks))
+	set := New(s)
+	hooks.AddOnRegisterSession(set.Track)
+	hooks.AddOnUnregisterSession(set.Forget)
+	return s, set
+}
+
+func TestSet_Sessions(t *testing.T) {
+	s, set := newTestSet()
+	a, b := newTestSession("a"), newTestSession("b")
+	for _, session := range []*testSession{a, b} {
+		if err := s.RegisterSession(context.Background(), session); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := set.StreamableHTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("%s request from session %s should not reach the MCP server", r.Method, r.Header.Get(sessionIDHeader))
+	}))
+	send := func(sessionID, method, uri string) {
+		t.Helper()
+		body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":{"uri":"` + uri + `"}}`
+		r := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		r.Header.Set(sessionIDHeader, sessionID)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"result"`) {
+			t.Fatalf("%s from %s = %d %s", method, sessionID, w.Code, w.Body.String())
+		}
+	}
+
+	send("a", methodSubscribe, "note://rooms/nook")
+	send("b", methodSubscribe, "note://rooms/nook")
+	send("b", methodSubscribe, "category://rooms")
+
+	// One session unsubscribing leaves the other's subscription alone
+	send("a", methodUnsubscribe, "note://rooms/nook")
+	set.NotifyUpdated("note://rooms/nook")
+	set.NotifyUpdated("category://rooms")
+	set.NotifyUpdated("note://people/simon")
+
+	if got := a.received(); len(got) != 0 {
+		t.Errorf("session a received %v, expected nothing after unsubscribing", got)
+	}
+	if got := b.received(); len(got) != 2 || got[0] != "note://rooms/nook" || got[1] != "category://rooms" {
+		t.Errorf("session b received %v, expected its two subscriptions", got)
+	}
+
+	// Subscriptions end with the session
+	s.UnregisterSession(context.Background(), "b")
+	if subscribers := set.Subscribers("category://rooms"); len(subscribers) != 0 {
+		t.Errorf("Subscribers() = %v after the session ended", subscribers)
+	}
+}
+
+func TestSet_UnknownSession(t *testing.T) {
+	_, set := newTestSet()
+
+	response := set.handle("forged", []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"note://rooms/nook"}}`))
+	if !strings.Contains(string(response), `"error"`) {
+		t.Errorf("handle() = %s, expected an error for a session the server doesn't know", response)
+	}
+	if subscribers := set.Subscribers("note://rooms/nook"); len(subscribers) != 0 {
+		t.Errorf("Subscribers() = %v, expected the unknown session not to be recorded", subscribers)
+	}
+}
+
+func TestSet_SSESendFails(t *testing.T) {
+	s, set := newTestSet()
+	// Registered with the MCP server, but without an event stream on the SSE server to answer on
+	if err := s.RegisterSession(context.Background(), newTestSession("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := set.SSE(server.NewSSEServer(s))
+	body := `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"note://rooms/nook"}}`
+	r := httptest.NewRequest(http.MethodPost, "/message?sessionId=a", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("subscribe = %d, expected %d when the response can't be sent", w.Code, http.StatusBadRequest)
+	}
+	if subscribers := set.Subscribers("note://rooms/nook"); len(subscribers) != 0 {
+		t.Errorf("Subscribers() = %v, expected the subscription to be dropped", subscribers)
+	}
+}
+
+func TestSet_NotifyForgetsEndedSessions(t *testing.T) {
+	_, set := newTestSet()
+	// Tracked, but gone from the server without the unregister hook running
+	set.Track(context.Background(), newTestSession("a"))
+	set.handle("a", []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"note://rooms/nook"}}`))
+
+	set.NotifyUpdated("note://rooms/nook")
+	if subscribers := set.Subscribers("note://rooms/nook"); len(subscribers) != 0 {
+		t.Errorf("Subscribers() = %v, expected the ended session to be forgotten", subscribers)
+	}
+}
+
+func TestSet_StreamableHTTPDelete(t *testing.T) {
+	_, set := newTestSet()
+	set.Track(context.Background(), newTestSession("a"))
+	set.handle("a", []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"note://rooms/nook"}}`))
+
+	passed := false
+	handler := set.StreamableHTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { passed = true }))
+	r := httptest.NewRequest(http.MethodDelete, "/mcp", nil)
+	r.Header.Set(sessionIDHeader, "a")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if !passed {
+		t.Error("DELETE should be passed through to the MCP server")
+	}
+	if subscribers := set.Subscribers("note://rooms/nook"); len(subscribers) != 0 {
+		t.Errorf("Subscribers() = %v after the session was deleted", subscribers)
+	}
+}
@@ -0,0 +1,56 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// BackupDirs are the vault subdirs included in every backup
+var BackupDirs = []string{vault.NOTES_DIR, vault.META_DIR}
+
+const noStoreMessage = "No storage backend is configured. Run `setup drive` to configure Google Drive, or set local_store_dir to use a local folder."
+
+// BackupTool returns the configured mcp.Tool for backing up the vault
+func BackupTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "backup_vault",
+		Description: fmt.Sprintf("Backs up the notes vault (the %v dirs) to the '%s' folder of the configured storage backend (e.g. Google Drive). Each backup is a versioned snapshot; only files that changed since earlier backups are uploaded.", BackupDirs, storage.BACKUP_DIR),
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]any{},
+		},
+	}
+}
+
+// BackupHandler creates a handler for uploading a snapshot of the vault to the storage backend
+func BackupHandler(ctx context.Context, cfg *config.Config, store storage.Store) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if store == nil {
+			return mcp.NewToolResultError(noStoreMessage), nil
+		}
+
+		result, err := store.BackupVault(cfg.ObsidianVaultPath, BackupDirs)
+		if err != nil {
+			logger.Error("Failed to back up vault", zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to back up vault: %v", err)), nil
+		}
+
+		logger.Info("Vault backed up successfully",
+			zap.String("snapshot", result.Snapshot.ID),
+			zap.Int("uploaded", result.Uploaded),
+			zap.Int("reused", result.Reused))
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully backed up %d files as snapshot %s (%d uploaded, %d unchanged)",
+			len(result.Snapshot.Files), result.Snapshot.ID, result.Uploaded, result.Reused)), nil
+	}
+}
@@ -0,0 +1,47 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RestoreTool returns the configured mcp.Tool for restoring the vault from a backup
+func RestoreTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "restore_vault",
+		Description: fmt.Sprintf("Restores the notes vault from the newest snapshot in the '%s' folder of the configured storage backend. Files in the snapshot OVERWRITE local copies; local files that aren't in the snapshot are kept. ALWAYS confirm with the user before calling this tool.", storage.BACKUP_DIR),
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]any{},
+		},
+	}
+}
+
+// RestoreHandler creates a handler for rebuilding the vault from the newest backup snapshot
+func RestoreHandler(ctx context.Context, cfg *config.Config, store storage.Store) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if store == nil {
+			return mcp.NewToolResultError(noStoreMessage), nil
+		}
+
+		snapshot, err := store.RestoreVault(cfg.ObsidianVaultPath)
+		if err != nil {
+			logger.Error("Failed to restore vault", zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to restore vault: %v", err)), nil
+		}
+
+		logger.Info("Vault restored successfully", zap.String("snapshot", snapshot.ID), zap.Int("files", len(snapshot.Files)))
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully restored %d files from snapshot %s (created %s)",
+			len(snapshot.Files), snapshot.ID, snapshot.CreatedAt)), nil
+	}
+}
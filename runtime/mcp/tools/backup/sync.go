@@ -0,0 +1,79 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"github.com/myungbeans/blueprince-mcp/runtime/vaultsync"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SyncTool returns the configured mcp.Tool for syncing notes with other devices
+func SyncTool() mcp.Tool {
+	return mcp.Tool{
+		Name: "sync_vault",
+		Description: fmt.Sprintf(`Two-way syncs the vault's notes with the '%s' folder of the configured storage backend, so notes taken on one device show up on the others.
+- Notes changed on only one side since the last sync are copied to the other side, including deletes.
+- Notes changed on both sides are conflicts: the version with the newer updated_at is kept and the other is saved next to it as "<name> (Conflicted copy <device> <time>).md". Tell the user about every conflicted copy so they can merge it by hand.
+- Set "dry_run" to preview the changes without writing anything.`, vaultsync.REMOTE_DIR),
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"dry_run": map[string]string{
+					"type":        "boolean",
+					"description": "If true, only report what would change",
+				},
+			},
+		},
+	}
+}
+
+// SyncHandler creates a handler for syncing the notes dir with the storage backend
+func SyncHandler(ctx context.Context, cfg *config.Config, store storage.Store) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if store == nil {
+			return mcp.NewToolResultError(noStoreMessage), nil
+		}
+		dryRun := request.GetBool("dry_run", false)
+
+		result, err := vaultsync.New(store, cfg.ObsidianVaultPath).Sync(dryRun)
+		if err != nil {
+			logger.Error("Failed to sync vault", zap.Bool("dry_run", dryRun), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to sync vault: %v", err)), nil
+		}
+
+		logger.Info("Vault synced successfully",
+			zap.Bool("dry_run", dryRun),
+			zap.Int("changes", len(result.Changes)),
+			zap.Int("unchanged", result.Unchanged))
+		return mcp.NewToolResultText(formatSyncResult(result)), nil
+	}
+}
+
+func formatSyncResult(result *vaultsync.Result) string {
+	verb := "Synced"
+	if result.DryRun {
+		verb = "Dry run:"
+	}
+	if len(result.Changes) == 0 {
+		return fmt.Sprintf("%s vault is up to date (%d notes unchanged)", verb, result.Unchanged)
+	}
+
+	lines := make([]string, len(result.Changes))
+	for i, change := range result.Changes {
+		lines[i] = fmt.Sprintf("%s: %s", change.Action, change.Path)
+		if change.ConflictCopy != "" {
+			lines[i] += fmt.Sprintf(" (other version saved as %s)", change.ConflictCopy)
+		}
+	}
+	return fmt.Sprintf("%s %d notes changed, %d unchanged:\n%s", verb, len(result.Changes), result.Unchanged, strings.Join(lines, "\n"))
+}
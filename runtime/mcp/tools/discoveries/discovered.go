@@ -0,0 +1,93 @@
+package discoveries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	knownResources "github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/discoveries"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/discoveries"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxRelated caps the related discoveries listed when a term is not discovered
+const maxRelated = 10
+
+func IsDiscoveredTool() mcp.Tool {
+	tool := mcp.Tool{
+		Name: "is_discovered",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"term": map[string]string{
+					"type":        "string",
+					"description": "Name of a room, person, item or concept, e.g. 'Nook' or 'Simon Jones'",
+				},
+			},
+			Required: []string{"term"},
+		},
+	}
+
+	tool.Description = `
+This Tool checks whether the player has documented a room, person, item or term in their notes.
+Discoveries are taken from note titles, primary subjects, tags and headings. Matching ignores case, punctuation and plurals.
+CRITICAL: Call this tool BEFORE sharing any information from outside the player's notes (training data, wikis, web searches).
+- If the term is discovered, outside information about it may be shared as the spoiler prevention rules allow.
+- If it is NOT discovered, do NOT share outside information about it, and do NOT mention it.
+The full list is available as the ` + knownResources.KnownURI + ` resource.
+`
+	return tool
+}
+
+// IsDiscoveredHandler creates a handler for looking a term up in the discovery registry
+func IsDiscoveredHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := request.GetArguments()
+		if params == nil {
+			return mcp.NewToolResultError("Missing arguments for is_discovered"), nil
+		}
+
+		term, err := utils.ExtractStringParam(params, "term")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
+		}
+		if utils.Slugify(term) == "" {
+			return mcp.NewToolResultError("term must contain letters or digits"), nil
+		}
+
+		registry, err := discoveries.Load(cfg.ObsidianVaultPath)
+		if err != nil {
+			logger.Error("Failed to load discoveries", zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load discoveries: %v", err)), nil
+		}
+
+		var sb strings.Builder
+		if d, ok := registry.Lookup(term); ok {
+			fmt.Fprintf(&sb, "DISCOVERED: '%s' is documented in the player's notes as a %s (%s).\n", term, d.Kind, d.Term)
+			if d.FirstSeen != "" {
+				fmt.Fprintf(&sb, "First seen: %s in %s\n", d.FirstSeen, d.SourceNote)
+			} else {
+				fmt.Fprintf(&sb, "First seen in: %s\n", d.SourceNote)
+			}
+			fmt.Fprintf(&sb, "Notes: %s\n", strings.Join(d.Notes, ", "))
+			sb.WriteString("Outside information about it may be shared only as the spoiler prevention rules allow.")
+			return mcp.NewToolResultText(sb.String()), nil
+		}
+
+		fmt.Fprintf(&sb, "NOT DISCOVERED: '%s' is not in the player's notes. Do NOT share outside information about it.\n", term)
+		if related := registry.Related(term); len(related) > 0 {
+			sb.WriteString("Documented discoveries with overlapping names (check whether the player meant one of these):\n")
+			for _, d := range related[:min(len(related), maxRelated)] {
+				fmt.Fprintf(&sb, "- %s (%s, %s)\n", d.Term, d.Kind, d.SourceNote)
+			}
+		}
+		return mcp.NewToolResultText(strings.TrimSpace(sb.String())), nil
+	}
+}
@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/guard"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
@@ -63,7 +63,7 @@ Expected Call:
 	return tool
 }
 
-func CreateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+func CreateHandler(ctx context.Context, cfg *config.Config, g *guard.Pipeline) server.ToolHandlerFunc {
 	logger := utils.Logger(ctx)
 
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -77,10 +77,6 @@ func CreateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
 		}
-		if err := spoilerCheck(content); err != nil {
-			logger.Warn("Content contains potential spoiler additions", zap.String("reason", err.Error()))
-			return mcp.NewToolResultError(fmt.Sprintf("Content validation failed: %v. Please provide only the user's direct observations without additional analysis or investigation prompts.", err)), nil
-		}
 
 		// Metadata validation
 		metadataRaw, ok := params["metadata"]
@@ -97,6 +93,9 @@ func CreateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 			logger.Error("Failed to parse metadata", zap.Error(err))
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid metadata: %v", err)), nil
 		}
+		if metadata.Screenshots, err = validateScreenshots(cfg, metadata.Screenshots); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid metadata: %v", err)), nil
+		}
 
 		// Path validation
 		notePath, err := utils.ExtractStringParam(params, "path")
@@ -136,6 +135,13 @@ func CreateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 		metadata.CreatedAt = now
 		metadata.UpdatedAt = now
 
+		// Guard the content as close to the write as possible, once everything else is known to be valid
+		guarded, failed := checkContent(ctx, cfg, g, "create_note", cleanPath, metadata, content)
+		if failed != nil {
+			return failed, nil
+		}
+		content = guarded.Content
+
 		fileContent, err := notes.CreateContent(metadata, content)
 		if err != nil {
 			logger.Error("Failed to create file content", zap.Error(err))
@@ -148,38 +154,15 @@ func CreateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to write note file: %v", err)), nil
 		}
 
-		logger.Info("Created note successfully", zap.String("path", notePath), zap.String("category", metadata.Category))
-		return mcp.NewToolResultText(fmt.Sprintf("Successfully created note: %s", notePath)), nil
-	}
-}
-
-// spoilerCheck checks content for patterns that suggest the LLM added spoiler-risk content
-func spoilerCheck(content string) error {
-	lowerContent := strings.ToLower(content)
-
-	// Check for common investigation section headers - LLM will likely structure additions in headers
-	investigationHeaders := []string{
-		"## analysis",
-		"## investigation",
-		"## questions",
-		"## next steps",
-		"## follow-up",
-		"## theories",
-		"## connections",
-		"## clues",
-		"## mysteries",
-		"## research",
-		"### investigation",
-		"### questions",
-		"### analysis",
-		"### theories",
-	}
-
-	for _, header := range investigationHeaders {
-		if strings.Contains(lowerContent, header) {
-			return fmt.Errorf("content contains investigation section header: '%s'", header)
+		if len(metadata.Screenshots) > 0 {
+			if err := linkScreenshots(cfg, cleanPath, metadata.Screenshots); err != nil {
+				// The note itself is saved, so report the failure without failing the call
+				logger.Warn("Failed to link screenshots to note", zap.String("path", notePath), zap.Error(err))
+				return guardedResult(fmt.Sprintf("Successfully created note: %s (but failed to link screenshots: %v)", notePath, err), guarded), nil
+			}
 		}
-	}
 
-	return nil
+		logger.Info("Created note successfully", zap.String("path", notePath), zap.String("category", metadata.Category))
+		return guardedResult(fmt.Sprintf("Successfully created note: %s", notePath), guarded), nil
+	}
 }
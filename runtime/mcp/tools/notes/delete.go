@@ -76,6 +76,10 @@ func DeleteHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to delete note file '%s': %v", notePath, err)), nil
 		}
 
+		if err := linkScreenshots(cfg, cleanPath, nil); err != nil {
+			logger.Warn("Failed to unlink screenshots from deleted note", zap.String("path", notePath), zap.Error(err))
+		}
+
 		logger.Info("Note deleted successfully", zap.String("path", notePath))
 		return mcp.NewToolResultText(fmt.Sprintf("Successfully deleted note: %s", notePath)), nil
 	}
@@ -0,0 +1,70 @@
+package notes
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/guard"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// checkContent runs the guard pipeline over content that tool is about to write to the note at cleanPath, and records
+// the decision in the vault's guard audit log. Every tool writing client authored note content must call it.
+// On rejection, or if the pipeline fails, the tool result to return is given instead of a guard.Result.
+func checkContent(ctx context.Context, cfg *config.Config, g *guard.Pipeline, tool, cleanPath string, metadata *notes.Metadata, content string) (*guard.Result, *mcp.CallToolResult) {
+	logger := utils.Logger(ctx)
+	path := filepath.ToSlash(cleanPath)
+
+	result, err := g.Check(guard.Document{Path: path, Metadata: metadata, Content: content})
+	if err != nil {
+		logger.Error("Failed to run the content guard", zap.String("path", cleanPath), zap.Error(err))
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Content validation could not run: %v", err))
+	}
+	// The audit log is for tuning the rules, so failing to write it shouldn't fail the write it describes
+	if err := guard.AppendAudit(cfg.ObsidianVaultPath, guard.NewAuditEntry(tool, path, result)); err != nil {
+		logger.Warn("Failed to record the guard decision", zap.String("path", cleanPath), zap.Error(err))
+	}
+	for _, finding := range result.Findings {
+		logger.Warn("Content guard finding",
+			zap.String("path", cleanPath),
+			zap.String("rule", finding.Rule),
+			zap.String("action", finding.Action),
+			zap.String("match", finding.Match))
+	}
+
+	if result.Rejected() {
+		text := fmt.Sprintf("Content validation failed:\n%s\nPlease provide only the user's direct observations without additional analysis or investigation prompts.",
+			result.Report(guard.ActionReject))
+		return nil, &mcp.CallToolResult{
+			Content: []mcp.Content{mcp.NewTextContent(text), mcp.NewTextContent(result.JSON())},
+			IsError: true,
+		}
+	}
+	return result, nil
+}
+
+// guardedResult returns a success result for text, followed by what the guard stripped or warned about
+func guardedResult(text string, result *guard.Result) *mcp.CallToolResult {
+	if len(result.Findings) == 0 {
+		return mcp.NewToolResultText(text)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(text)
+	if stripped := result.Report(guard.ActionStrip); stripped != "" {
+		fmt.Fprintf(&sb, "\n\nRemoved from the note before saving:\n%s", stripped)
+	}
+	if warnings := result.Report(guard.ActionWarn); warnings != "" {
+		fmt.Fprintf(&sb, "\n\nWarnings (saved anyway). If any of this did not come from the player, fix the note with update_note:\n%s", warnings)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.NewTextContent(sb.String()), mcp.NewTextContent(result.JSON())},
+	}
+}
@@ -8,11 +8,19 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
 	"go.uber.org/zap"
 )
 
+// Views of a note returned by read_note
+const (
+	viewFull = "full"
+	// viewStripExternal hides the blocks tagged as external, leaving what the player wrote or photographed
+	viewStripExternal = "strip_external"
+)
+
 // ReadTool returns the configured mcp.Tool for reading notes
 func ReadTool() mcp.Tool {
 	return mcp.Tool{
@@ -25,6 +33,11 @@ func ReadTool() mcp.Tool {
 					"type":        "string",
 					"description": "Path to the note file relative to the notes directory (e.g., 'people/simon_jones.md', 'rooms/nook_tiger_paintings.md')",
 				},
+				"view": map[string]any{
+					"type":        "string",
+					"description": "Optional. \"full\" (default) returns the whole note. \"strip_external\" leaves out the blocks tagged as external context, showing only what the player wrote or saw in screenshots.",
+					"enum":        []string{viewFull, viewStripExternal},
+				},
 			},
 			Required: []string{"path"},
 		},
@@ -74,7 +87,20 @@ func ReadHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to read note file '%s': %v", notePath, err)), nil
 		}
 
-		logger.Info("Note read successfully", zap.String("path", notePath))
-		return mcp.NewToolResultText(string(content)), nil
+		view := viewFull
+		if v, ok := params["view"].(string); ok && v != "" {
+			view = v
+		}
+		switch view {
+		case viewFull:
+			logger.Info("Note read successfully", zap.String("path", notePath))
+			return mcp.NewToolResultText(string(content)), nil
+		case viewStripExternal:
+			stripped, removed := notes.StripExternal(string(content))
+			logger.Info("Note read successfully", zap.String("path", notePath), zap.Int("externalBlocksHidden", removed))
+			return mcp.NewToolResultText(stripped), nil
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid view '%s'. Must be one of: %v", view, []string{viewFull, viewStripExternal})), nil
+		}
 	}
 }
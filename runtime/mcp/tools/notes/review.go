@@ -0,0 +1,56 @@
+package notes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/guard"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+)
+
+// ReviewGuardLogTool returns the configured mcp.Tool for summarizing the guard audit log
+func ReviewGuardLogTool() mcp.Tool {
+	return mcp.Tool{
+		Name: "review_guard_log",
+		Description: "Summarizes the content guard's audit log: how many note writes were checked, rejected, warned about or stripped, which rules fired and how often, " +
+			"and the same text being caught repeatedly (e.g. a client retrying an analysis section after a rejection). Matched text is not logged, only its hash. " +
+			"Use this when the player wants to tune the guard rules.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"since": map[string]string{
+					"type":        "string",
+					"description": "Optional. Only summarize decisions from this date (YYYY-MM-DD) or RFC3339 timestamp on. Defaults to the whole log.",
+				},
+			},
+		},
+	}
+}
+
+// ReviewGuardLogHandler creates a handler for summarizing the guard audit log
+func ReviewGuardLogHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		since, err := utils.ParseTimeBound(request.GetString("since", ""), false)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid 'since': %v", err)), nil
+		}
+
+		entries, skipped, err := guard.LoadAudit(cfg.ObsidianVaultPath, since)
+		if err != nil {
+			logger.Error("Failed to load the guard audit log", zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load the guard audit log: %v", err)), nil
+		}
+
+		text := guard.Summarize(entries).Format()
+		if skipped > 0 {
+			text += fmt.Sprintf("\n\n%d malformed line(s) in %s were skipped.", skipped, guard.AuditPath(cfg.ObsidianVaultPath))
+		}
+		return mcp.NewToolResultText(text), nil
+	}
+}
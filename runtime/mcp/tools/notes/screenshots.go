@@ -0,0 +1,38 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// validateScreenshots checks that every named screenshot is in the vault's ./screenshots dir.
+// Returns the names cleaned and slash separated, ready to store in the note's metadata.
+func validateScreenshots(cfg *config.Config, names []string) ([]string, error) {
+	cleaned := make([]string, 0, len(names))
+	for _, name := range names {
+		cleanName, err := utils.ValidatePath(name)
+		if err != nil {
+			return nil, err
+		}
+		fullPath, err := utils.BuildSecurePath(cfg.ObsidianVaultPath, vault.SCREENSHOT_DIR, cleanName)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+			return nil, fmt.Errorf("screenshot not found: '%s'", name)
+		}
+		cleaned = append(cleaned, filepath.ToSlash(cleanName))
+	}
+	return cleaned, nil
+}
+
+// linkScreenshots records in the screenshot manifest which screenshots the note at cleanPath embeds
+func linkScreenshots(cfg *config.Config, cleanPath string, names []string) error {
+	return screenshots.LinkNote(cfg.ObsidianVaultPath, filepath.ToSlash(cleanPath), names)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/guard"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
@@ -26,7 +27,7 @@ func UpdateTool() mcp.Tool {
 }
 
 // UpdateHandler creates a handler for updating existing notes
-func UpdateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+func UpdateHandler(ctx context.Context, cfg *config.Config, g *guard.Pipeline) server.ToolHandlerFunc {
 	logger := utils.Logger(ctx)
 
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -41,12 +42,6 @@ func UpdateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
 		}
 
-		// Check for spoiler-risk content patterns
-		if err := spoilerCheck(content); err != nil {
-			logger.Warn("Content contains potential spoiler additions", zap.String("reason", err.Error()))
-			return mcp.NewToolResultError(fmt.Sprintf("Content validation failed: %v. Please provide only the user's direct observations without additional analysis or investigation prompts.", err)), nil
-		}
-
 		// Extract and validate metadata parameter
 		metadataRaw, ok := params["metadata"]
 		if !ok {
@@ -62,6 +57,9 @@ func UpdateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 			logger.Error("Failed to parse metadata", zap.Error(err))
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid metadata: %v", err)), nil
 		}
+		if metadata.Screenshots, err = validateScreenshots(cfg, metadata.Screenshots); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid metadata: %v", err)), nil
+		}
 
 		// Extract and validate path parameter
 		notePath, err := utils.ExtractStringParam(params, "path")
@@ -101,6 +99,13 @@ func UpdateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 		metadata.UpdatedAt = time.Now().Format(time.RFC3339)
 		// Note: We trust the MCP client to preserve created_at from the existing note
 
+		// Guard the content as close to the write as possible, once everything else is known to be valid
+		guarded, failed := checkContent(ctx, cfg, g, "update_note", cleanPath, metadata, content)
+		if failed != nil {
+			return failed, nil
+		}
+		content = guarded.Content
+
 		// Create updated file content
 		fileContent, err := notes.CreateContent(metadata, content)
 		if err != nil {
@@ -114,7 +119,14 @@ func UpdateHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFu
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to write updated note file: %v", err)), nil
 		}
 
+		// Always relink so screenshots dropped from the note are unlinked too
+		if err := linkScreenshots(cfg, cleanPath, metadata.Screenshots); err != nil {
+			// The note itself is saved, so report the failure without failing the call
+			logger.Warn("Failed to link screenshots to note", zap.String("path", notePath), zap.Error(err))
+			return guardedResult(fmt.Sprintf("Successfully updated note: %s (but failed to link screenshots: %v)", notePath, err), guarded), nil
+		}
+
 		logger.Info("Note updated successfully", zap.String("path", notePath), zap.String("category", metadata.Category))
-		return mcp.NewToolResultText(fmt.Sprintf("Successfully updated note: %s", notePath)), nil
+		return guardedResult(fmt.Sprintf("Successfully updated note: %s", notePath), guarded), nil
 	}
 }
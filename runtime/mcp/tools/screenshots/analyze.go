@@ -2,12 +2,11 @@ package screenshots
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
-	"os"
+	"path/filepath"
 
 	"github.com/myungbeans/blueprince-mcp/cmd/config"
-	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
 	"go.uber.org/zap"
 
@@ -25,17 +24,25 @@ func AnalyzeTool() mcp.Tool {
 					"type":        "string",
 					"description": "Specific filename to look for in local vault",
 				},
+				"mask": maskProperty(fmt.Sprintf("Named regions to blank out before analysis. Defaults to the HUD counters %v. Pass an empty list to see the whole screenshot.", utils.HUDRegions)),
+				"room_label": map[string]string{
+					"type":        "boolean",
+					"description": "If true (the default), a crop of the room label is returned as a second image",
+				},
 			},
 		},
 	}
 
 	tool.Description = `
-This Tool responds with b64 encoded data from the screenshot image file requesteed. 
+This Tool responds with the requested screenshot as an image, compressed to fit within the client's context limits.
 
 This Tool is part of a multi-step WORKFLOW that is made up of 
 1. download_screenshots
-2. analyze_screenshot
-3. create_note
+2. next_screenshot_to_analyze
+3. analyze_screenshot
+4. create_note
+
+Calling this tool marks the screenshot as analyzed in the screenshot queue.
 
 WORKFLOW: 
 After getting a successful response from this tool, you should:
@@ -44,12 +51,13 @@ After getting a successful response from this tool, you should:
 - NEVER make any logical leaps or connections to other notes
 - NEVER add follow up questions or things to investigate
 - ALWAYS be careful to avoid the possibility of spoilers
-- ALWAYS ignore HUD elements in the top left and top right portions of the screen with counters for resources (such as steps, dice, keys, gems, and coins).
+- ALWAYS ignore HUD elements in the top left and top right portions of the screen with counters for resources (such as steps, dice, keys, gems, and coins). These are masked in black by default.
 - ALWAYS annotate any text found in the image EXACTLY as it is on the screen.
-- ALWAYS includendicate which room the screenshot is from (the room is indicated in the bottom right corner of the screenshot)
+- ALWAYS indicate which room the screenshot is from. Read it from the room label image that follows the screenshot (the bottom right corner of the screenshot)
 - If there is ANY part of your analysis that you are unsure of, indicate so by flagging it with "==NEEDS USER VERIFICATION==" in the note, being specific about which specific parts of your analysis you are unsure of.
 
-2. Call the create_note tool with your analysis.
+2. Call the create_note tool with your analysis. ALWAYS list the screenshot's file_name under metadata.screenshots so the note embeds it.
+- If the response says the screenshot is already linked to notes, prefer read_note and update_note on those notes over creating a new one.
 `
 	return tool
 }
@@ -60,34 +68,35 @@ func AnalyzeHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerF
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		params := request.GetArguments()
 		if params == nil {
-			return mcp.NewToolResultError("Missing arguments for view_screenshot"), nil
+			return mcp.NewToolResultError("Missing arguments for analyze_screenshot"), nil
 		}
 
 		// Extract and validate path parameter
 		imgName, err := utils.ExtractStringParam(params, "file_name")
-		if err != nil || imgName == "" {
+		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
 		}
 
-		// Build the path, validate it
-		cleanFilePath, err := utils.ValidatePath(imgName)
-		fullPath, err := utils.BuildSecurePath(cfg.ObsidianVaultPath, vault.SCREENSHOT_DIR, cleanFilePath)
+		mask := request.GetStringSlice("mask", utils.HUDRegions)
+		result, err := imageResult(cfg, imgName, mask, request.GetBool("room_label", true))
 		if err != nil {
-			logger.Warn("Invalid screenshot path", zap.String("path", fullPath), zap.Error(err))
-			return mcp.NewToolResultError(err.Error()), nil
+			logger.Error("Failed to load screenshot", zap.String("file_name", imgName), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load screenshot '%s': %v", imgName, err)), nil
 		}
 
-		// Check if file exists and read the content
-		content, err := os.ReadFile(fullPath)
-		if err != nil {
-			logger.Error("Failed to read screenshot file", zap.String("filePath", fullPath), zap.Error(err))
-			if os.IsNotExist(err) {
-				return mcp.NewToolResultError(fmt.Sprintf("Screenshot not found: '%s'", fullPath)), nil
-			}
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to read screenshot file '%s': %v", fullPath, err)), nil
+		// imageResult has already validated the name
+		cleanName, _ := utils.ValidatePath(imgName)
+		cleanName = filepath.ToSlash(cleanName)
+		if err := screenshots.MarkAnalyzed(cfg.ObsidianVaultPath, cleanName); err != nil {
+			logger.Warn("Failed to mark screenshot as analyzed", zap.String("file_name", imgName), zap.Error(err))
 		}
-		b64Data := base64.StdEncoding.EncodeToString(content)
 
-		return mcp.NewToolResultText(fmt.Sprintf("data:image/jpeg;base64,%s", b64Data)), nil
+		// Point at notes that already embed this screenshot so they get updated instead of duplicated
+		if m, err := screenshots.LoadManifest(cfg.ObsidianVaultPath); err != nil {
+			logger.Warn("Failed to load screenshot manifest", zap.Error(err))
+		} else if entry, ok := m.Entries[cleanName]; ok && len(entry.Notes) > 0 {
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("Already linked to notes: %v", entry.Notes)))
+		}
+		return result, nil
 	}
 }
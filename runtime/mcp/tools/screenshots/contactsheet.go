@@ -0,0 +1,171 @@
+package screenshots
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultContactSheetColumns is how many thumbnails go in each row of a contact sheet
+	DefaultContactSheetColumns = 4
+	// DefaultContactSheetCells is how many thumbnails a contact sheet holds unless asked otherwise
+	DefaultContactSheetCells = 24
+	// MaxContactSheetCells keeps each thumbnail legible once the sheet is scaled to fit the image budget
+	MaxContactSheetCells = 48
+)
+
+func ContactSheetTool() mcp.Tool {
+	tool := mcp.Tool{
+		Name: "contact_sheet",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"file_names": map[string]any{
+					"type":        "array",
+					"description": "Screenshots in the vault's ./screenshots dir to include, in order. If empty, screenshots are picked by capture time using from, to and status.",
+					"items": map[string]string{
+						"type": "string",
+					},
+				},
+				"from": map[string]string{
+					"type":        "string",
+					"description": "Only include screenshots captured at or after this time. Either a date (YYYY-MM-DD) or an RFC3339 timestamp.",
+				},
+				"to": map[string]string{
+					"type":        "string",
+					"description": "Only include screenshots captured up to this time. Either a date (YYYY-MM-DD, the whole day is included) or an RFC3339 timestamp.",
+				},
+				"status": map[string]any{
+					"type":        "string",
+					"description": "Only include screenshots with this analysis status, e.g. \"new\" for the ones not analyzed yet",
+					"enum":        screenshots.Statuses,
+				},
+				"columns": map[string]any{
+					"type":        "integer",
+					"description": fmt.Sprintf("Thumbnails per row. Defaults to %d.", DefaultContactSheetColumns),
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": fmt.Sprintf("Max number of thumbnails (1-%d). Defaults to %d.", MaxContactSheetCells, DefaultContactSheetCells),
+				},
+			},
+		},
+	}
+
+	tool.Description = `
+This Tool returns a single image with a grid of screenshot thumbnails, each labeled with its number and file name, followed by the numbered list of file names.
+Use it to give the player a visual overview of a session's screenshots in one image, e.g. all "new" screenshots from a given day.
+
+WORKFLOW: Show the contact sheet to the player and let them pick which screenshots to analyze, by number or file name.
+- Call analyze_screenshot for each screenshot they pick.
+- Call set_screenshot_status with status "skipped" for the ones they do not want notes for.
+- NEVER describe or interpret the contents of the thumbnails beyond what is needed to tell them apart.
+`
+	return tool
+}
+
+// ContactSheetHandler creates a handler for compositing screenshot thumbnails into a single image
+func ContactSheetHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		columns := request.GetInt("columns", DefaultContactSheetColumns)
+		if columns < 1 {
+			return mcp.NewToolResultError(fmt.Sprintf("columns must be at least 1, got %d", columns)), nil
+		}
+		limit := request.GetInt("limit", DefaultContactSheetCells)
+		if limit < 1 || limit > MaxContactSheetCells {
+			return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d, got %d", MaxContactSheetCells, limit)), nil
+		}
+
+		names, err := contactSheetNames(cfg, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(names) == 0 {
+			return mcp.NewToolResultText("No screenshots matched"), nil
+		}
+		omitted := max(0, len(names)-limit)
+		names = names[:len(names)-omitted]
+
+		cells := make([]utils.ContactSheetCell, len(names))
+		for i, name := range names {
+			thumb, err := screenshots.LoadThumbnail(cfg.ObsidianVaultPath, name)
+			if err != nil {
+				logger.Warn("Failed to load thumbnail", zap.String("file_name", name), zap.Error(err))
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to load thumbnail of '%s': %v", name, err)), nil
+			}
+			cells[i] = utils.ContactSheetCell{Image: thumb, Label: fmt.Sprintf("%d: %s", i+1, name)}
+		}
+
+		sheet := utils.ContactSheet(cells, columns, utils.ThumbnailEdge)
+		img, err := utils.EncodeToBudget(sheet, cfg.ImageMaxBytes())
+		if err != nil {
+			logger.Error("Failed to encode contact sheet", zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to encode contact sheet: %v", err)), nil
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Contact sheet of %d screenshot(s), left to right and top to bottom:\n", len(names))
+		for i, name := range names {
+			fmt.Fprintf(&sb, "%d. %s\n", i+1, name)
+		}
+		if omitted > 0 {
+			fmt.Fprintf(&sb, "%d more screenshot(s) matched but did not fit. Narrow the date range or raise the limit to see them.\n", omitted)
+		}
+		return mcp.NewToolResultImage(sb.String(), img.Data, img.MimeType), nil
+	}
+}
+
+// contactSheetNames returns the screenshots requested by name, or else those matching the date range and status in capture order
+func contactSheetNames(cfg *config.Config, request mcp.CallToolRequest) ([]string, error) {
+	status := request.GetString("status", "")
+	if status != "" && !slices.Contains(screenshots.Statuses, status) {
+		return nil, fmt.Errorf("invalid status '%s'. Must be one of: %v", status, screenshots.Statuses)
+	}
+
+	if requested := request.GetStringSlice("file_names", nil); len(requested) > 0 {
+		names := make([]string, len(requested))
+		for i, name := range requested {
+			if _, err := screenshotPath(cfg, name); err != nil {
+				return nil, err
+			}
+			cleanName, _ := utils.ValidatePath(name)
+			names[i] = filepath.ToSlash(cleanName)
+		}
+		return names, nil
+	}
+
+	from, err := utils.ParseTimeBound(request.GetString("from", ""), false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'from': %w", err)
+	}
+	to, err := utils.ParseTimeBound(request.GetString("to", ""), true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'to': %w", err)
+	}
+	timeline, err := screenshots.LoadTimeline(cfg.ObsidianVaultPath, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load screenshot timeline: %w", err)
+	}
+
+	var names []string
+	for _, entry := range timeline {
+		if status != "" && entry.CurrentStatus() != status {
+			continue
+		}
+		names = append(names, entry.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,212 @@
+package screenshots
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	unitPixels  = "px"
+	unitPercent = "percent"
+)
+
+func CropTool() mcp.Tool {
+	tool := mcp.Tool{
+		Name: "crop_screenshot",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"file_name": map[string]string{
+					"type":        "string",
+					"description": "File name of the screenshot file. The file must be directly in the vault's ./screenshots dir",
+				},
+				"region": map[string]string{
+					"type":        "string",
+					"description": fmt.Sprintf("Named region to crop. Ignored if a rectangle is given. Presets: %v. Built in regions: %v. More can be configured under images.regions.", utils.RegionNames(utils.PresetRegions), utils.RegionNames(utils.NamedRegions)),
+				},
+				"x": map[string]string{
+					"type":        "number",
+					"description": "Left edge of the rectangle to crop, in the given unit",
+				},
+				"y": map[string]string{
+					"type":        "number",
+					"description": "Top edge of the rectangle to crop, in the given unit",
+				},
+				"width": map[string]string{
+					"type":        "number",
+					"description": "Width of the rectangle to crop, in the given unit",
+				},
+				"height": map[string]string{
+					"type":        "number",
+					"description": "Height of the rectangle to crop, in the given unit",
+				},
+				"unit": map[string]any{
+					"type":        "string",
+					"description": "Unit of x, y, width and height: pixels of the original screenshot, or percent (0-100) of its width and height. Defaults to px.",
+					"enum":        []string{unitPixels, unitPercent},
+				},
+				"tile": map[string]string{
+					"type":        "boolean",
+					"description": "If true, split the screenshot (or the cropped area) into overlapping tiles returned as separate images",
+				},
+				"tile_size": map[string]any{
+					"type":        "integer",
+					"description": fmt.Sprintf("Max width and height of each tile in pixels. Defaults to %d.", utils.DefaultTileEdge),
+				},
+				"overlap": map[string]any{
+					"type":        "number",
+					"description": fmt.Sprintf("Percent (0-50) of each tile shared with its neighbours. Defaults to %.0f.", utils.DefaultTileOverlap*100),
+				},
+			},
+			Required: []string{"file_name"},
+		},
+	}
+
+	tool.Description = `
+This Tool returns part of a screenshot at full resolution, so small text (letters, blackboards, book pages, signs) stays legible.
+- Crop a named "region" (e.g. "room_label" for the room name), or a rectangle given by "x", "y", "width" and "height" in pixels or percent.
+- Set "tile" to split the screenshot (or the cropped area) into overlapping tiles. Each tile is returned as a separate image, in reading order, after a text block listing where each tile sits in the original screenshot.
+
+Use this tool when analyze_screenshot shows text that is too small to read. ALWAYS transcribe text EXACTLY as it appears. Text that is cut off at the edge of a tile appears whole in the neighbouring, overlapping tile.
+`
+	return tool
+}
+
+// CropHandler creates a handler for cropping and tiling screenshots
+func CropHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		imgName := request.GetString("file_name", "")
+		fullPath, err := screenshotPath(cfg, imgName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		img, _, _, err := utils.LoadImage(fullPath)
+		if err != nil {
+			logger.Warn("Failed to load screenshot", zap.String("path", fullPath), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load screenshot '%s': %v", imgName, err)), nil
+		}
+
+		tile := request.GetBool("tile", false)
+		rect, err := cropRect(cfg, request, img.Bounds())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if rect.Empty() && !tile {
+			return mcp.NewToolResultError(`Specify a "region", a rectangle ("x", "y", "width", "height") or set "tile"`), nil
+		}
+		if rect.Empty() {
+			rect = img.Bounds()
+		}
+
+		tileSize := request.GetInt("tile_size", utils.DefaultTileEdge)
+		overlap := request.GetFloat("overlap", utils.DefaultTileOverlap*100) / 100
+		if tileSize < 64 {
+			return mcp.NewToolResultError("tile_size must be at least 64 pixels"), nil
+		}
+		if overlap < 0 || overlap > 0.5 {
+			return mcp.NewToolResultError("overlap must be between 0 and 50 percent"), nil
+		}
+
+		tiles := []utils.Tile{{Bounds: rect}}
+		if tile {
+			tiles = utils.TileBounds(rect, tileSize, overlap)
+		}
+
+		// Tiles share the budget so the whole response stays within it
+		budget := cfg.ImageMaxBytes() / len(tiles)
+		lines := make([]string, len(tiles))
+		images := make([]mcp.Content, len(tiles))
+		downscaled := false
+		for i, t := range tiles {
+			cropped, err := utils.CropImage(img, t.Bounds)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			encoded, err := utils.EncodeToBudget(cropped, budget)
+			if err != nil {
+				logger.Warn("Failed to encode crop", zap.String("path", fullPath), zap.Error(err))
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to encode crop of '%s': %v. Try a smaller area or larger tile_size.", imgName, err)), nil
+			}
+
+			lines[i] = fmt.Sprintf("image %d: x=%d y=%d width=%d height=%d", i+1, t.Bounds.Min.X, t.Bounds.Min.Y, t.Bounds.Dx(), t.Bounds.Dy())
+			if tile {
+				lines[i] += fmt.Sprintf(" (row %d, col %d)", t.Row+1, t.Col+1)
+			}
+			// Many tiles can outgrow their share of the budget, say so rather than pass them off as full resolution
+			if encoded.Width != t.Bounds.Dx() || encoded.Height != t.Bounds.Dy() {
+				lines[i] += fmt.Sprintf(", downscaled to %dx%d", encoded.Width, encoded.Height)
+				downscaled = true
+			}
+			images[i] = mcp.NewImageContent(encoded.Data, encoded.MimeType)
+		}
+
+		summary := fmt.Sprintf("Screenshot '%s' (%dx%d px), %d crop(s) in pixels of the original:\n%s",
+			imgName, img.Bounds().Dx(), img.Bounds().Dy(), len(tiles), strings.Join(lines, "\n"))
+		if downscaled {
+			summary += "\nSome images were downscaled to fit the response size limit. For full resolution, crop a smaller area or use a larger tile_size so fewer tiles share the limit."
+		}
+		return &mcp.CallToolResult{
+			Content: append([]mcp.Content{mcp.NewTextContent(summary)}, images...),
+		}, nil
+	}
+}
+
+// cropRect reads the requested crop from the request. An empty rectangle means no crop was requested.
+func cropRect(cfg *config.Config, request mcp.CallToolRequest, bounds image.Rectangle) (image.Rectangle, error) {
+	args := request.GetArguments()
+	_, hasX := args["x"]
+	_, hasY := args["y"]
+	_, hasWidth := args["width"]
+	_, hasHeight := args["height"]
+
+	if !hasX && !hasY && !hasWidth && !hasHeight {
+		name := request.GetString("region", "")
+		if name == "" {
+			return image.Rectangle{}, nil
+		}
+		regions, err := lookupRegions(cfg, []string{name})
+		if err != nil {
+			return image.Rectangle{}, err
+		}
+		return regions[0].Rect(bounds), nil
+	}
+
+	if !hasWidth || !hasHeight {
+		return image.Rectangle{}, fmt.Errorf("width and height are required when cropping a rectangle")
+	}
+	x := request.GetFloat("x", 0)
+	y := request.GetFloat("y", 0)
+	width := request.GetFloat("width", 0)
+	height := request.GetFloat("height", 0)
+
+	switch unit := request.GetString("unit", unitPixels); unit {
+	case unitPercent:
+		region := utils.Region{X: x / 100, Y: y / 100, Width: width / 100, Height: height / 100}
+		if err := region.Validate(); err != nil {
+			return image.Rectangle{}, err
+		}
+		return region.Rect(bounds), nil
+
+	case unitPixels:
+		rect := image.Rect(int(x), int(y), int(x+width), int(y+height)).Add(bounds.Min)
+		if width <= 0 || height <= 0 || !rect.In(bounds) {
+			return image.Rectangle{}, fmt.Errorf("rectangle %v must have a positive size and lie within the %dx%d screenshot", rect, bounds.Dx(), bounds.Dy())
+		}
+		return rect, nil
+
+	default:
+		return image.Rectangle{}, fmt.Errorf("unit must be '%s' or '%s', got '%s'", unitPixels, unitPercent, unit)
+	}
+}
@@ -31,15 +31,18 @@ It can be configured to batch download multiple files or a single file.
 - If the param "file_name" is an empty string, all files directly in the pre-configured Google Drive folder will be downloaded.
 - If the param "file_name" is not an empty string, the tool will attempt to download only the specified file.
 Under the hood, this tool moves successfully downloaded files into an archived folder in Drive.	
+Screenshots whose exact content is already in the vault are skipped and not returned, so the same image is never analyzed twice.
 
 This Tool is part of a multi-step WORKFLOW that is made up of 
 1. download_screenshots
-2. analyze_screenshot
-3. create_note
+2. next_screenshot_to_analyze
+3. analyze_screenshot
+4. create_note
 
-WORKFLOW: After getting a successful response from this tool, you should iterate over each downloaded file. For each fileName in the response's comma separated list of fileNames:
+WORKFLOW: Downloaded screenshots are added to the screenshot queue. After getting a successful response from this tool, work through the queue:
+- Call the next_screenshot_to_analyze tool to get the next fileName. Stop when the queue is empty.
 - Call the analyze_screenshot tool. This tool will ask the MCP Host to analyze the contents of the screenshot and format them in the appropriate format for a create_note tool call.
-- Call the create_note tool. This tool will store the outputs of analyze_screenshot into a note containing the analyzed contents of the screenshot.
+- Call the create_note tool with the fileName listed under metadata.screenshots. This tool will store the outputs of analyze_screenshot into a note containing the analyzed contents of the screenshot, and take the screenshot off the queue.
 `
 	return tool
 }
@@ -68,6 +71,10 @@ func DownloadHandler(ctx context.Context, store storage.Store) server.ToolHandle
 			return mcp.NewToolResultError(err.Error()), err
 		}
 
+		if len(files) == 0 {
+			return mcp.NewToolResultText("No new screenshots downloaded. All matching files were already in the vault."), nil
+		}
+
 		return mcp.NewToolResultText(strings.Join(files, ",")), nil
 	}
 }
@@ -0,0 +1,133 @@
+package screenshots
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// roomLabelRegion is the preset extracted by analyze_screenshot
+const roomLabelRegion = "room_label"
+
+// maskProperty is the input schema of the "mask" param shared by the screenshot tools
+func maskProperty(description string) map[string]any {
+	return map[string]any{
+		"type":        "array",
+		"description": description + fmt.Sprintf(" Presets: %v. Built in regions: %v. More can be configured under images.regions.", utils.RegionNames(utils.PresetRegions), utils.RegionNames(utils.NamedRegions)),
+		"items": map[string]string{
+			"type": "string",
+		},
+	}
+}
+
+// screenshotPath validates imgName and returns the full path of the screenshot in the vault's ./screenshots dir
+func screenshotPath(cfg *config.Config, imgName string) (string, error) {
+	return screenshots.FilePath(cfg.ObsidianVaultPath, imgName)
+}
+
+// lookupRegions resolves region names against the built in and configured regions
+func lookupRegions(cfg *config.Config, names []string) ([]utils.Region, error) {
+	all := cfg.Regions()
+	regions := make([]utils.Region, len(names))
+	for i, name := range names {
+		region, ok := all[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown region '%s'. Must be one of: %v", name, utils.RegionNames(all))
+		}
+		regions[i] = region
+	}
+	return regions, nil
+}
+
+// imageResult loads a screenshot from the vault's ./screenshots dir, blanks out the regions named in mask and returns
+// it as image content that fits the configured byte budget.
+// If withRoomLabel is set, a crop of the room label follows as a second image.
+func imageResult(cfg *config.Config, imgName string, mask []string, withRoomLabel bool) (*mcp.CallToolResult, error) {
+	fullPath, err := screenshotPath(cfg, imgName)
+	if err != nil {
+		return nil, err
+	}
+	masked, err := lookupRegions(cfg, mask)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := cfg.ImageMaxBytes()
+	if !withRoomLabel && len(masked) == 0 {
+		// Nothing to change, so the original file can be sent as is if it fits
+		img, err := utils.CompressImageToBudget(fullPath, budget)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultImage(imageSummary(imgName, img, nil), img.Data, img.MimeType), nil
+	}
+
+	src, _, _, err := utils.LoadImage(fullPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var label image.Image
+	if withRoomLabel {
+		labelRegion, err := lookupRegions(cfg, []string{roomLabelRegion})
+		if err != nil {
+			return nil, err
+		}
+		if label, err = utils.ExtractRegion(src, labelRegion[0]); err != nil {
+			return nil, err
+		}
+		// The label is small, so a fifth of the budget keeps it at full resolution
+		budget -= budget / 5
+	}
+
+	img, err := utils.EncodeToBudget(utils.MaskRegions(src, masked), budget)
+	if err != nil {
+		return nil, err
+	}
+	result := mcp.NewToolResultImage(imageSummary(imgName, img, mask), img.Data, img.MimeType)
+
+	if label != nil {
+		encoded, err := utils.EncodeToBudget(label, cfg.ImageMaxBytes()/5)
+		if err != nil {
+			return nil, err
+		}
+		result.Content = append(result.Content,
+			mcp.NewTextContent("Room label (bottom right corner of the screenshot):"),
+			mcp.NewImageContent(encoded.Data, encoded.MimeType))
+	}
+	return result, nil
+}
+
+// thumbnailResult returns the cached thumbnail of a screenshot as image content
+func thumbnailResult(cfg *config.Config, imgName string) (*mcp.CallToolResult, error) {
+	if _, err := screenshotPath(cfg, imgName); err != nil {
+		return nil, err
+	}
+	cleanName, _ := utils.ValidatePath(imgName)
+	thumb, err := screenshots.LoadThumbnail(cfg.ObsidianVaultPath, cleanName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := utils.EncodeJPEG(thumb, utils.ThumbnailQuality)
+	if err != nil {
+		return nil, err
+	}
+	bounds := thumb.Bounds()
+	summary := fmt.Sprintf("Thumbnail of screenshot '%s' (%dx%d jpeg, %d bytes)", imgName, bounds.Dx(), bounds.Dy(), len(data))
+	return mcp.NewToolResultImage(summary, base64.StdEncoding.EncodeToString(data), "image/jpeg"), nil
+}
+
+func imageSummary(imgName string, img *utils.CompressedImageResult, mask []string) string {
+	summary := fmt.Sprintf("Screenshot '%s' (%dx%d %s, %d bytes)", imgName, img.Width, img.Height, img.Format, img.CompressedSize)
+	if len(mask) > 0 {
+		summary += fmt.Sprintf(". Masked in black: %v", mask)
+	}
+	return summary
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
 	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
@@ -25,7 +27,7 @@ var sources = []string{LOCAL_SRC, GOOGLE_DRIVE_SRC}
 func ListTool() mcp.Tool {
 	return mcp.Tool{
 		Name:        "list_screenshots",
-		Description: "Lists all screenshots within the pre-configured Google Drive folder. A successful response includes a comma separated list of file names",
+		Description: "Lists all screenshots within the pre-configured Google Drive folder. A successful response includes a comma separated list of file names. For the local source, set \"from\" and/or \"to\" to list the vault's screenshots captured in that date range in chronological order, grouped by day, e.g. to reconstruct what was seen during a play session.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]any{
@@ -34,6 +36,14 @@ func ListTool() mcp.Tool {
 					"description": "Source to lookup screenshots from.",
 					"enum":        sources,
 				},
+				"from": map[string]string{
+					"type":        "string",
+					"description": "Only list screenshots captured at or after this time. Either a date (YYYY-MM-DD, start of day in local time) or an RFC3339 timestamp. Local source only.",
+				},
+				"to": map[string]string{
+					"type":        "string",
+					"description": "Only list screenshots captured up to this time. Either a date (YYYY-MM-DD, the whole day is included) or an RFC3339 timestamp. Local source only.",
+				},
 			},
 		},
 	}
@@ -53,10 +63,26 @@ func ListHandler(ctx context.Context, cfg *config.Config, store storage.Store) s
 			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
 		}
 
+		from, err := utils.ParseTimeBound(request.GetString("from", ""), false)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid 'from': %v", err)), nil
+		}
+		to, err := utils.ParseTimeBound(request.GetString("to", ""), true)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid 'to': %v", err)), nil
+		}
+		byDate := !from.IsZero() || !to.IsZero()
+
 		switch source {
 		case LOCAL_SRC:
+			if byDate {
+				return listLocalTimeline(cfg, from, to)
+			}
 			return listLocalScreenshots(cfg)
 		case GOOGLE_DRIVE_SRC:
+			if byDate {
+				return mcp.NewToolResultError("Filtering by date is only supported for the local source"), nil
+			}
 			files, err := store.ListFiles()
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), err
@@ -82,3 +108,14 @@ func listLocalScreenshots(cfg *config.Config) (*mcp.CallToolResult, error) {
 	resultText := "Notes:\n" + strings.Join(relativeFilePaths, "\n")
 	return mcp.NewToolResultText(resultText), nil
 }
+
+func listLocalTimeline(cfg *config.Config, from, to time.Time) (*mcp.CallToolResult, error) {
+	timeline, err := screenshots.LoadTimeline(cfg.ObsidianVaultPath, from, to)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Error loading screenshot timeline: %v", err)), nil
+	}
+	if len(timeline) == 0 {
+		return mcp.NewToolResultText("No screenshots captured in the given range"), nil
+	}
+	return mcp.NewToolResultText(screenshots.FormatTimeline(timeline)), nil
+}
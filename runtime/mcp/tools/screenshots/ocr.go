@@ -0,0 +1,234 @@
+package screenshots
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/ocr"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func OCRTool() mcp.Tool {
+	tool := mcp.Tool{
+		Name: "ocr_screenshot",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"file_name": map[string]string{
+					"type":        "string",
+					"description": "File name of the screenshot file. The file must be directly in the vault's ./screenshots dir",
+				},
+				"region": map[string]string{
+					"type":        "string",
+					"description": fmt.Sprintf("Named region to read. Ignored if a rectangle is given. Presets: %v. Built in regions: %v. More can be configured under images.regions.", utils.RegionNames(utils.PresetRegions), utils.RegionNames(utils.NamedRegions)),
+				},
+				"x": map[string]string{
+					"type":        "number",
+					"description": "Left edge of the rectangle to read, in the given unit",
+				},
+				"y": map[string]string{
+					"type":        "number",
+					"description": "Top edge of the rectangle to read, in the given unit",
+				},
+				"width": map[string]string{
+					"type":        "number",
+					"description": "Width of the rectangle to read, in the given unit",
+				},
+				"height": map[string]string{
+					"type":        "number",
+					"description": "Height of the rectangle to read, in the given unit",
+				},
+				"unit": map[string]any{
+					"type":        "string",
+					"description": "Unit of x, y, width and height: pixels of the original screenshot, or percent (0-100) of its width and height. Defaults to px.",
+					"enum":        []string{unitPixels, unitPercent},
+				},
+				"refresh": map[string]string{
+					"type":        "boolean",
+					"description": "If true, read the text again even if this area was read before",
+				},
+			},
+			Required: []string{"file_name"},
+		},
+	}
+
+	tool.Description = `
+This Tool reads the text in a screenshot with an offline OCR engine and stores it with the screenshot, so search_screenshot_text can find it later.
+- Read the whole screenshot, a named "region", or a rectangle given by "x", "y", "width" and "height" in pixels or percent.
+- The response lists the recognized text, then each line with its confidence (0-100) and bounding box in pixels of the original screenshot.
+- Areas that were read before return the stored text unless "refresh" is set.
+
+OCR works best on a tight crop around the text (a letter, a blackboard, a sign). Lines with low confidence are likely misread:
+ALWAYS check them against the screenshot (e.g. with crop_screenshot) before transcribing them into a note.
+`
+	return tool
+}
+
+// OCRHandler creates a handler for reading the text in screenshots with the given OCR engine
+func OCRHandler(ctx context.Context, cfg *config.Config, engine ocr.Engine) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		imgName := request.GetString("file_name", "")
+		fullPath, err := screenshotPath(cfg, imgName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		name := filepath.ToSlash(filepath.Clean(imgName))
+
+		img, _, _, err := utils.LoadImage(fullPath)
+		if err != nil {
+			logger.Warn("Failed to load screenshot", zap.String("path", fullPath), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load screenshot '%s': %v", imgName, err)), nil
+		}
+
+		rect, err := cropRect(cfg, request, img.Bounds())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		area := ocrArea(request, rect)
+		if rect.Empty() {
+			rect = img.Bounds()
+		}
+
+		if !request.GetBool("refresh", false) {
+			m, err := screenshots.LoadManifest(cfg.ObsidianVaultPath)
+			if err != nil {
+				logger.Warn("Failed to load the screenshot manifest", zap.Error(err))
+			} else if entry, ok := m.Entries[name]; ok && entry.OCR[area] != nil && entry.OCR[area].Area == ocr.BoxFromRect(rect) {
+				// A named region resolves to a different rectangle if its config or the screenshot's size changed since
+				return mcp.NewToolResultText(formatOCR(imgName, area, entry.OCR[area], true)), nil
+			}
+		}
+
+		cropped, err := utils.CropImage(img, rect)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		lines, err := engine.Recognize(ctx, cropped)
+		if err != nil {
+			logger.Error("OCR failed", zap.String("path", fullPath), zap.String("engine", engine.Name()), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read text in '%s': %v", imgName, err)), nil
+		}
+
+		// The crop starts at 0,0, so shift the boxes back onto the original screenshot
+		for i := range lines {
+			lines[i].Box.X += rect.Min.X
+			lines[i].Box.Y += rect.Min.Y
+		}
+		result := &ocr.Result{
+			Engine:       engine.Name(),
+			RecognizedAt: time.Now().UTC().Format(time.RFC3339),
+			Area:         ocr.BoxFromRect(rect),
+			Lines:        lines,
+		}
+
+		text := formatOCR(imgName, area, result, false)
+		if err := screenshots.SetOCR(cfg.ObsidianVaultPath, name, area, result); err != nil {
+			logger.Warn("Failed to store OCR result", zap.String("file_name", name), zap.Error(err))
+			text += fmt.Sprintf("\n\nWarning: the text could not be stored with the screenshot: %v", err)
+		}
+		return mcp.NewToolResultText(text), nil
+	}
+}
+
+// ocrArea names the area read by an OCR request: the region name, the rectangle or the whole screenshot
+func ocrArea(request mcp.CallToolRequest, rect image.Rectangle) string {
+	if rect.Empty() {
+		return screenshots.OCRFullArea
+	}
+	args := request.GetArguments()
+	if _, hasWidth := args["width"]; !hasWidth {
+		return request.GetString("region", "")
+	}
+	return fmt.Sprintf("%d,%d %dx%d", rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy())
+}
+
+func formatOCR(imgName, area string, result *ocr.Result, cached bool) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Text in '%s' (area: %s", imgName, area)
+	if cached {
+		fmt.Fprintf(&sb, ", read by %s at %s. Set refresh to read it again", result.Engine, result.RecognizedAt)
+	}
+	sb.WriteString("):\n")
+
+	if len(result.Lines) == 0 {
+		sb.WriteString("No text recognized.")
+		return sb.String()
+	}
+	sb.WriteString(result.Text())
+	sb.WriteString("\n\nLines (confidence, x,y widthxheight in px):")
+	for _, line := range result.Lines {
+		fmt.Fprintf(&sb, "\n[%.0f] (%d,%d %dx%d) %s", line.Confidence, line.Box.X, line.Box.Y, line.Box.Width, line.Box.Height, line.Text)
+	}
+	return sb.String()
+}
+
+func SearchTextTool() mcp.Tool {
+	tool := mcp.Tool{
+		Name: "search_screenshot_text",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"query": map[string]string{
+					"type":        "string",
+					"description": "Text to look for. Matching ignores case.",
+				},
+			},
+			Required: []string{"query"},
+		},
+	}
+
+	tool.Description = `
+This Tool searches the text read from screenshots by ocr_screenshot, so text that only appeared in game (letters, signs, blackboards) can be found.
+The response lists each matching screenshot with the matching lines, the area they were read from and the notes that link the screenshot.
+Only screenshots that were passed through ocr_screenshot are searched.
+`
+	return tool
+}
+
+// SearchTextHandler creates a handler for searching the text recognized in screenshots
+func SearchTextHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		query := strings.TrimSpace(request.GetString("query", ""))
+		if query == "" {
+			return mcp.NewToolResultError("query is required"), nil
+		}
+
+		m, err := screenshots.LoadManifest(cfg.ObsidianVaultPath)
+		if err != nil {
+			logger.Error("Failed to load the screenshot manifest", zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to search screenshot text: %v", err)), nil
+		}
+
+		matches := m.SearchText(query)
+		if len(matches) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No screenshot text matches '%s'.", query)), nil
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%d screenshot(s) with text matching '%s':", len(matches), query)
+		for _, match := range matches {
+			fmt.Fprintf(&sb, "\n\n%s", match.Name)
+			if len(match.Notes) > 0 {
+				fmt.Fprintf(&sb, " (notes: %s)", strings.Join(match.Notes, ", "))
+			}
+			for _, line := range match.Lines {
+				fmt.Fprintf(&sb, "\n  %s", line)
+			}
+		}
+		return mcp.NewToolResultText(sb.String()), nil
+	}
+}
@@ -0,0 +1,121 @@
+package screenshots
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func NextTool() mcp.Tool {
+	tool := mcp.Tool{
+		Name: "next_screenshot_to_analyze",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]any{},
+		},
+	}
+
+	tool.Description = `
+This Tool returns the next screenshot in the vault that still needs a note, oldest capture first, along with how many are left.
+The queue is stored in the vault, so a batch can be picked up again in a later conversation.
+- Screenshots are "new" until analyze_screenshot is called on them, then "analyzed".
+- They become "noted" once a note lists them under metadata.screenshots, which takes them out of the queue.
+- Screenshots that do not merit a note should be marked "skipped" with set_screenshot_status.
+
+WORKFLOW: Call this tool, then for the returned file_name:
+1. Call analyze_screenshot with the file_name.
+2. Call create_note (or update_note) with the file_name listed under metadata.screenshots.
+3. Call this tool again until the queue is empty.
+`
+	return tool
+}
+
+// NextHandler creates a handler for picking the next screenshot off the analysis queue
+func NextHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		next, m, err := screenshots.NextInQueue(cfg.ObsidianVaultPath)
+		if err != nil {
+			logger.Error("Failed to read the screenshot queue", zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read the screenshot queue: %v", err)), nil
+		}
+
+		counts := screenshots.FormatStatusCounts(m.StatusCounts())
+		if next == nil {
+			return mcp.NewToolResultText(fmt.Sprintf("The screenshot queue is empty. %s", counts)), nil
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Next screenshot to analyze: %s\n", next.Name)
+		fmt.Fprintf(&sb, "Status: %s\n", next.CurrentStatus())
+		if next.CapturedAt != "" {
+			fmt.Fprintf(&sb, "Captured at: %s\n", next.CapturedAt)
+		}
+		fmt.Fprintf(&sb, "%d screenshot(s) left in the queue. %s", len(m.Queue()), counts)
+		return mcp.NewToolResultText(sb.String()), nil
+	}
+}
+
+func StatusTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        "set_screenshot_status",
+		Description: "Sets where a screenshot is in the analysis queue. Use \"skipped\" for screenshots that do not merit a note, so next_screenshot_to_analyze stops returning them, and \"new\" to queue a screenshot up again. Screenshots become \"noted\" by listing them under a note's metadata.screenshots instead.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"file_name": map[string]string{
+					"type":        "string",
+					"description": "Screenshot in the vault's ./screenshots dir",
+				},
+				"status": map[string]any{
+					"type":        "string",
+					"description": "New status of the screenshot",
+					"enum":        []string{screenshots.StatusNew, screenshots.StatusAnalyzed, screenshots.StatusSkipped},
+				},
+			},
+			Required: []string{"file_name", "status"},
+		},
+	}
+}
+
+// StatusHandler creates a handler for setting the analysis status of a screenshot
+func StatusHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := request.GetArguments()
+		if params == nil {
+			return mcp.NewToolResultError("Missing arguments for set_screenshot_status"), nil
+		}
+
+		fileName, err := utils.ExtractStringParam(params, "file_name")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
+		}
+		status, err := utils.ExtractStringParam(params, "status")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
+		}
+
+		cleanFilePath, err := utils.ValidatePath(fileName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := screenshots.SetStatus(cfg.ObsidianVaultPath, filepath.ToSlash(cleanFilePath), status); err != nil {
+			logger.Warn("Failed to set screenshot status", zap.String("file_name", fileName), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to set status of '%s': %v", fileName, err)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Marked screenshot '%s' as %s", fileName, status)), nil
+	}
+}
@@ -0,0 +1,114 @@
+package screenshots
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func SimilarTool() mcp.Tool {
+	tool := mcp.Tool{
+		Name: "find_similar_screenshots",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"file_name": map[string]string{
+					"type":        "string",
+					"description": "Screenshot in the vault's ./screenshots dir to find near-duplicates of. If empty, all groups of near-duplicate screenshots are returned.",
+				},
+				"max_distance": map[string]any{
+					"type":        "integer",
+					"description": fmt.Sprintf("Max perceptual hash distance (0-64) for two screenshots to count as similar. Lower is stricter. Defaults to %d.", screenshots.DefaultSimilarityDistance),
+				},
+			},
+		},
+	}
+
+	tool.Description = `
+This Tool finds screenshots in the local vault that show (nearly) the same scene, using a perceptual hash of each image.
+- If the param "file_name" is set, the response lists the screenshots similar to that file, closest first.
+- If the param "file_name" is empty, the response lists every group of near-duplicate screenshots, one group per line.
+
+Use this tool BEFORE analyze_screenshot when working through a batch of screenshots.
+Only analyze ONE screenshot from each group of near-duplicates, unless the user asks otherwise.
+`
+	return tool
+}
+
+// SimilarHandler creates a handler for finding near-duplicate screenshots
+func SimilarHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		fileName := request.GetString("file_name", "")
+		maxDistance := request.GetInt("max_distance", screenshots.DefaultSimilarityDistance)
+		if maxDistance < 0 || maxDistance > 64 {
+			return mcp.NewToolResultError(fmt.Sprintf("max_distance must be between 0 and 64, got %d", maxDistance)), nil
+		}
+
+		var result string
+		imgDir := filepath.Join(cfg.ObsidianVaultPath, vault.SCREENSHOT_DIR)
+		err := screenshots.Update(cfg.ObsidianVaultPath, func(m *screenshots.Manifest) error {
+			// Pick up any screenshots that entered the vault without going through download_screenshots
+			if _, err := m.Index(imgDir); err != nil {
+				return err
+			}
+
+			if fileName != "" {
+				cleanFilePath, err := utils.ValidatePath(fileName)
+				if err != nil {
+					return err
+				}
+				matches, err := m.Similar(filepath.ToSlash(cleanFilePath), maxDistance)
+				if err != nil {
+					return err
+				}
+				result = formatMatches(fileName, matches)
+				return nil
+			}
+
+			result = formatGroups(m.SimilarGroups(maxDistance))
+			return nil
+		})
+		if err != nil {
+			logger.Warn("Failed to find similar screenshots", zap.String("file_name", fileName), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to find similar screenshots: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
+	}
+}
+
+func formatMatches(fileName string, matches []screenshots.Match) string {
+	if len(matches) == 0 {
+		return fmt.Sprintf("No screenshots similar to '%s' found.", fileName)
+	}
+
+	lines := make([]string, len(matches))
+	for i, match := range matches {
+		lines[i] = fmt.Sprintf("%s (distance: %d)", match.Name, match.Distance)
+	}
+	return fmt.Sprintf("Screenshots similar to '%s':\n%s", fileName, strings.Join(lines, "\n"))
+}
+
+func formatGroups(groups [][]string) string {
+	if len(groups) == 0 {
+		return "No near-duplicate screenshots found."
+	}
+
+	lines := make([]string, len(groups))
+	for i, group := range groups {
+		lines[i] = strings.Join(group, ",")
+	}
+	return "Groups of near-duplicate screenshots:\n" + strings.Join(lines, "\n")
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/myungbeans/blueprince-mcp/cmd/config"
-	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -13,9 +12,6 @@ import (
 	"go.uber.org/zap"
 )
 
-// TODO: Images exceed max length allowed in a conversation on Claude Desktop.
-// Needs to be batched or streamed. WIP
-
 func ViewTool() mcp.Tool {
 	tool := mcp.Tool{
 		Name: "view_screenshot",
@@ -26,22 +22,27 @@ func ViewTool() mcp.Tool {
 					"type":        "string",
 					"description": "File name of the screenshot file. The file must be directly in the vault's ./screenshots dir",
 				},
+				"mask": maskProperty("Named regions to blank out, e.g. the HUD counters."),
+				"thumbnail": map[string]string{
+					"type":        "boolean",
+					"description": "If true, return a small cached thumbnail instead of the full screenshot. Useful to tell screenshots apart without spending the full image budget. mask is ignored.",
+				},
 			},
 		},
 	}
 
 	tool.Description = `
-This Tool displays the contents of a screenshot file.
+This Tool displays the contents of a screenshot file. Viewing a screenshot does not change its status in the screenshot queue.
 
-This Tool is part of a multi-step WORKFLOW that is made up of 
+Use it to look at a screenshot, e.g. to compare near-duplicates or check a detail while updating a note.
+Screenshots are processed through the queue, in a multi-step WORKFLOW that is made up of 
 1. download_screenshots
-2. view_screenshot
+2. next_screenshot_to_analyze
 3. analyze_screenshot
 4. create_note
 
-WORKFLOW: After getting a successful response from this tool, you should iterate over each downloaded file. For each fileName in the response's comma separated list of fileNames:
-- Immediately after the response from view_screenshot, call the analyze_screenshot tool. This tool will ask the MCP Host to analyze the contents of the screenshot and format them in the appropriate format for a create_note tool call.
-- Call the create_note tool. This tool will store the outputs of analyze_screenshot into a note containing the analyzed contents of the screenshot.
+WORKFLOW: To process a screenshot rather than just look at it, call analyze_screenshot instead, which marks it as analyzed, then create_note with its fileName listed under metadata.screenshots, which marks it as noted.
+If after viewing a screenshot it should not get a note (e.g. a near-duplicate), call set_screenshot_status with status "skipped" so it leaves the queue.
 `
 	return tool
 }
@@ -58,39 +59,24 @@ func ViewHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc
 
 		// Extract and validate path parameter
 		imgName, err := utils.ExtractStringParam(params, "file_name")
-		if err != nil || imgName == "" {
+		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
 		}
 
-		// Build the path, validate it
-		cleanFilePath, err := utils.ValidatePath(imgName)
-		fullPath, err := utils.BuildSecurePath(cfg.ObsidianVaultPath, vault.SCREENSHOT_DIR, cleanFilePath)
-		if err != nil {
-			logger.Warn("Invalid screenshot path", zap.String("path", fullPath), zap.Error(err))
-			return mcp.NewToolResultError(err.Error()), nil
+		if request.GetBool("thumbnail", false) {
+			result, err := thumbnailResult(cfg, imgName)
+			if err != nil {
+				logger.Warn("Failed to load thumbnail", zap.String("file_name", imgName), zap.Error(err))
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to load thumbnail of '%s': %v", imgName, err)), nil
+			}
+			return result, nil
 		}
 
-		// Toggle aspect ratios as needed
-		smImg, err := utils.CompressImage(fullPath, 200, 200, 70)
+		result, err := imageResult(cfg, imgName, request.GetStringSlice("mask", nil), false)
 		if err != nil {
-			logger.Warn("Failed to compress the image", zap.String("path", fullPath), zap.Error(err))
-			return mcp.NewToolResultError(err.Error()), nil
+			logger.Warn("Failed to load screenshot", zap.String("file_name", imgName), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load screenshot '%s': %v", imgName, err)), nil
 		}
-
-		mimeType := utils.GetMimeType(fullPath)
-		return mcp.NewToolResultImage("Screenshot loaded successfully", smImg.Data, mimeType), nil
-
-		// // Check if file exists and read the content
-		// content, err := os.ReadFile(fullPath)
-		// if err != nil {
-		// 	logger.Error("Failed to read screenshot file", zap.String("filePath", fullPath), zap.Error(err))
-		// 	if os.IsNotExist(err) {
-		// 		return mcp.NewToolResultError(fmt.Sprintf("Screenshot not found: '%s'", fullPath)), nil
-		// 	}
-		// 	return mcp.NewToolResultError(fmt.Sprintf("Failed to read screenshot file '%s': %v", fullPath, err)), nil
-		// }
-		// b64Data := base64.StdEncoding.EncodeToString(content)
-
-		// return mcp.NewToolResultText(fmt.Sprintf("data:image/jpeg;base64,%s", b64Data)), nil
+		return result, nil
 	}
 }
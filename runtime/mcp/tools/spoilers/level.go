@@ -0,0 +1,97 @@
+package spoilers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/cmd/config"
+	"github.com/myungbeans/blueprince-mcp/runtime/mcp/resources/rules"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/spoilers"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+	"go.uber.org/zap"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// levelDefault clears a saved level so the configured one applies again
+const levelDefault = "default"
+
+func LevelTool() mcp.Tool {
+	tool := mcp.Tool{
+		Name: "set_spoiler_level",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"level": map[string]any{
+					"type":        "string",
+					"description": fmt.Sprintf("Spoiler level to use, or %q to go back to the level set in the server config", levelDefault),
+					"enum":        append(append([]string{}, spoilers.Levels...), levelDefault),
+				},
+				"category": map[string]any{
+					"type":        "string",
+					"description": "Optional. Only use the level for notes in this category, e.g. strict for puzzles but full for items",
+					"enum":        notes.Categories,
+				},
+			},
+			Required: []string{"level"},
+		},
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`
+This Tool sets how much information from outside the player's notes may be shared. The choice is saved in the vault and
+the spoiler prevention rules at ` + rules.SpoilerRulesURI + ` change to match right away.
+ONLY call this tool when the player explicitly asks for a different spoiler level. NEVER suggest a less protective level.
+Levels, from most to least protective:
+`)
+	for _, level := range spoilers.Levels {
+		fmt.Fprintf(&sb, "- %s: %s\n", level, spoilers.Summaries[level])
+	}
+	sb.WriteString("After calling this tool, read " + rules.SpoilerRulesURI + " again and follow the updated rules.\n")
+	tool.Description = sb.String()
+	return tool
+}
+
+// LevelHandler creates a handler for saving the player's spoiler level
+func LevelHandler(ctx context.Context, cfg *config.Config) server.ToolHandlerFunc {
+	logger := utils.Logger(ctx)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := request.GetArguments()
+		if params == nil {
+			return mcp.NewToolResultError("Missing arguments for set_spoiler_level"), nil
+		}
+
+		level, err := utils.ExtractStringParam(params, "level")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Parameter validation failed: %v", err)), nil
+		}
+		category, _ := params["category"].(string)
+		if level == levelDefault {
+			level = ""
+		}
+
+		if err := spoilers.SetLevel(cfg.ObsidianVaultPath, category, level); err != nil {
+			logger.Warn("Failed to set spoiler level", zap.String("level", level), zap.String("category", category), zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to set spoiler level: %v", err)), nil
+		}
+
+		policy, err := spoilers.Effective(cfg.ObsidianVaultPath, cfg.Spoilers)
+		if err != nil {
+			logger.Error("Failed to load spoiler settings", zap.Error(err))
+			return mcp.NewToolResultError(fmt.Sprintf("Saved the spoiler level but failed to read it back: %v", err)), nil
+		}
+		logger.Info("Set spoiler level", zap.String("level", level), zap.String("category", category))
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Spoiler level: %s. %s\n", policy.DefaultLevel(), spoilers.Summaries[policy.DefaultLevel()])
+		for _, override := range policy.Overrides() {
+			fmt.Fprintf(&sb, "- %s notes: %s. %s\n", override.Category, override.Level, spoilers.Summaries[override.Level])
+		}
+		fmt.Fprintf(&sb, "Read %s again and follow the updated rules.", rules.SpoilerRulesURI)
+		return mcp.NewToolResultText(sb.String()), nil
+	}
+}
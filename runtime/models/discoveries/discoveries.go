@@ -0,0 +1,227 @@
+package discoveries
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// Kinds of discoveries
+const (
+	KindRoom   = "room"
+	KindPerson = "person"
+	KindItem   = "item"
+	// KindTerm is anything else the player wrote about: puzzles, lore, tags and headings
+	KindTerm = "term"
+)
+
+// categoryKinds maps note categories to the kind of their primary subjects
+var categoryKinds = map[string]string{
+	"rooms":  KindRoom,
+	"people": KindPerson,
+	"items":  KindItem,
+}
+
+// Discovery is a room, person, item or term the player has documented in their notes
+type Discovery struct {
+	// Term is the name as first written by the player
+	Term string `json:"term"`
+	// Key is the slugified term that lookups are matched against
+	Key  string `json:"key"`
+	Kind string `json:"kind"`
+	// FirstSeen is the creation time of the earliest note naming the discovery, or empty if no note says
+	FirstSeen string `json:"first_seen,omitempty"`
+	// SourceNote is the note the discovery was first seen in, relative to the notes dir
+	SourceNote string `json:"source_note"`
+	// Notes lists every note naming the discovery
+	Notes []string `json:"notes"`
+
+	firstSeen time.Time
+}
+
+// Registry is every discovery documented in the vault's notes, by key
+type Registry struct {
+	discoveries map[string]*Discovery
+}
+
+// Load builds the registry from the notes in the vault
+func Load(vaultPath string) (*Registry, error) {
+	all, err := notes.LoadAll(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	return Build(all), nil
+}
+
+// Build derives the registry from note titles, primary subjects, tags and markdown headings.
+// The primary subjects of room, people and item notes are discoveries of that kind; everything else is a term.
+func Build(all []*notes.Note) *Registry {
+	r := &Registry{discoveries: make(map[string]*Discovery)}
+	for _, note := range all {
+		kind := categoryKinds[noteCategory(note)]
+		if kind == "" {
+			kind = KindTerm
+		}
+
+		r.add(note, note.Metadata.Title, KindTerm)
+		r.add(note, note.Metadata.PrimarySubject, kind)
+		for _, tag := range note.Metadata.Tags {
+			if slices.Contains(notes.Categories, tag) {
+				// Nearly every note is tagged with its own category
+				continue
+			}
+			r.add(note, tag, KindTerm)
+		}
+		for _, heading := range headings(note.Content) {
+			r.add(note, heading, KindTerm)
+		}
+	}
+	return r
+}
+
+// add records that note names term
+func (r *Registry) add(note *notes.Note, term, kind string) {
+	term = strings.TrimSpace(term)
+	key := utils.Slugify(term)
+	if key == "" {
+		return
+	}
+	created := note.Created()
+
+	d, ok := r.discoveries[key]
+	if !ok {
+		r.discoveries[key] = &Discovery{
+			Term:       term,
+			Key:        key,
+			Kind:       kind,
+			FirstSeen:  formatTime(created),
+			SourceNote: note.Path,
+			Notes:      []string{note.Path},
+			firstSeen:  created,
+		}
+		return
+	}
+
+	// A room, person or item is more specific than a term
+	if d.Kind == KindTerm && kind != KindTerm {
+		d.Kind = kind
+	}
+	if !slices.Contains(d.Notes, note.Path) {
+		d.Notes = append(d.Notes, note.Path)
+	}
+	// Notes are loaded by path, so only an earlier known creation time moves the first sighting
+	if !created.IsZero() && (d.firstSeen.IsZero() || created.Before(d.firstSeen)) {
+		d.Term = term
+		d.FirstSeen = formatTime(created)
+		d.SourceNote = note.Path
+		d.firstSeen = created
+	}
+}
+
+// All returns every discovery, sorted by kind and then key
+func (r *Registry) All() []*Discovery {
+	all := make([]*Discovery, 0, len(r.discoveries))
+	for _, d := range r.discoveries {
+		all = append(all, d)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Kind != all[j].Kind {
+			return all[i].Kind < all[j].Kind
+		}
+		return all[i].Key < all[j].Key
+	})
+	return all
+}
+
+// Lookup returns the discovery matching term, ignoring case, punctuation and a plural "s" or "es"
+func (r *Registry) Lookup(term string) (*Discovery, bool) {
+	for _, candidate := range keyVariants(term) {
+		if d, ok := r.discoveries[candidate]; ok {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// Related returns the discoveries that contain term as whole words, or that term contains, excluding an exact match.
+// e.g. "tiger" relates to "nook_tiger_paintings".
+func (r *Registry) Related(term string) []*Discovery {
+	variants := keyVariants(term)
+	exact, _ := r.Lookup(term)
+
+	var related []*Discovery
+	for _, d := range r.All() {
+		if d == exact {
+			continue
+		}
+		for _, key := range variants {
+			if containsWords(d.Key, key) || containsWords(key, d.Key) {
+				related = append(related, d)
+				break
+			}
+		}
+	}
+	return related
+}
+
+// keyVariants returns the key of term along with its plural and singular forms, or nothing if term has no key
+func keyVariants(term string) []string {
+	key := utils.Slugify(term)
+	if key == "" {
+		return nil
+	}
+	return []string{key, key + "s", key + "es", strings.TrimSuffix(key, "s"), strings.TrimSuffix(key, "es")}
+}
+
+// containsWords reports whether the words of slug b appear in order within slug a
+func containsWords(a, b string) bool {
+	return strings.Contains("_"+a+"_", "_"+b+"_")
+}
+
+// headings returns the text of the markdown headings in a note's body
+func headings(content string) []string {
+	_, body, err := notes.ParseContent(content)
+	if err != nil {
+		body = content
+	}
+
+	var found []string
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		text := strings.TrimLeft(line, "#")
+		// "#tag" is an Obsidian tag, not a heading
+		if !strings.HasPrefix(text, " ") {
+			continue
+		}
+		found = append(found, strings.TrimSpace(strings.TrimRight(text, "#")))
+	}
+	return found
+}
+
+// noteCategory returns the note's category from its frontmatter, or from the dir it is in
+func noteCategory(note *notes.Note) string {
+	if note.Metadata.Category != "" {
+		return note.Metadata.Category
+	}
+	category, _, _ := strings.Cut(note.Path, "/")
+	return category
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
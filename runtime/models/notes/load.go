@@ -0,0 +1,74 @@
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// Note is a note file in the vault's notes dir
+type Note struct {
+	// Path is relative to the notes dir, with forward slashes, e.g. rooms/nook_tiger_paintings.md
+	Path     string
+	Metadata *Metadata
+	// Content is the whole file, frontmatter included
+	Content string
+}
+
+// LoadAll reads every markdown note in the vault's notes dir, sorted by path.
+// Notes with broken frontmatter are loaded with empty Metadata rather than failing the whole vault.
+func LoadAll(vaultPath string) ([]*Note, error) {
+	notesDir := filepath.Join(vaultPath, vault.NOTES_DIR)
+	if _, err := os.Stat(notesDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	files, err := utils.ListFiles(notesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []*Note
+	for _, rel := range files {
+		if filepath.Ext(rel) != ".md" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(notesDir, rel))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read note '%s': %w", rel, err)
+		}
+		metadata, _, err := ParseContent(string(data))
+		if err != nil {
+			metadata = &Metadata{}
+		}
+		loaded = append(loaded, &Note{Path: filepath.ToSlash(rel), Metadata: metadata, Content: string(data)})
+	}
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].Path < loaded[j].Path })
+	return loaded, nil
+}
+
+// Updated returns when the note was last updated according to its frontmatter, or the zero time if it doesn't say
+func (n *Note) Updated() time.Time {
+	for _, value := range []string{n.Metadata.UpdatedAt, n.Metadata.CreatedAt} {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Created returns when the note was created according to its frontmatter, falling back to when it was last updated.
+// It is the zero time if the frontmatter has neither.
+func (n *Note) Created() time.Time {
+	for _, value := range []string{n.Metadata.CreatedAt, n.Metadata.UpdatedAt} {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -2,8 +2,10 @@ package notes
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,6 +19,10 @@ type Metadata struct {
 	Status         string   `json:"status" yaml:"status"`
 	CreatedAt      string   `json:"created_at,omitempty" yaml:"created_at,omitempty"`
 	UpdatedAt      string   `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	// Screenshots are file names in the vault's screenshots dir that the note was written from
+	Screenshots []string `json:"screenshots,omitempty" yaml:"screenshots,omitempty"`
+	// Provenance is computed from the content's source blocks whenever the note is written
+	Provenance *Provenance `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 }
 
 func GetMCPSchema() mcp.ToolInputSchema {
@@ -61,12 +67,19 @@ func GetMCPSchema() mcp.ToolInputSchema {
 						"description": "Investigation status",
 						"enum":        []string{"complete", "needs_investigation", "active_investigation", "theory", "confirmed"},
 					},
+					"screenshots": map[string]any{
+						"type":        "array",
+						"description": "Optional. File names of the screenshots (in the vault's ./screenshots dir) this note was written from, e.g. the file_name passed to analyze_screenshot. They are embedded in the note and linked back to it.",
+						"items": map[string]string{
+							"type": "string",
+						},
+					},
 				},
 				"required": []string{"title", "category", "primary_subject", "tags", "confidence", "status"},
 			},
 			"content": map[string]string{
 				"type":        "string",
-				"description": "Raw markdown content containing ONLY the user's observations and input. NEVER add investigation prompts, analysis questions, or additional sections not explicitly provided by the user. Preserve exactly what the player observed without speculation or gameplay hints.",
+				"description": "Raw markdown content containing ONLY the user's observations and input. NEVER add investigation prompts, analysis questions, or additional sections not explicitly provided by the user. Preserve exactly what the player observed without speculation or gameplay hints. Untagged content is the player's own. Put text read off a screenshot in a '> [!screenshot]' callout and any context from outside the player's notes in a '> [!external]' callout (or a fenced block with the info string 'source: screenshot' / 'source: external'), so it can be told apart and hidden.",
 			},
 		},
 		Required: []string{"path", "metadata", "content"},
@@ -96,8 +109,8 @@ func ParseMetadata(metadataMap map[string]any) (*Metadata, error) {
 		return nil, fmt.Errorf("Invalid category '%s'. Must be one of: %v", metadata.Category, Categories)
 	}
 
-	// Parse primary_subject (not stored in metadata struct but validated)
-	primarySubject, ok := metadataMap["category"].(string)
+	// Parse primary_subject
+	primarySubject, ok := metadataMap["primary_subject"].(string)
 	if !ok {
 		return nil, fmt.Errorf("primary subject must be a string")
 	}
@@ -143,6 +156,21 @@ func ParseMetadata(metadataMap map[string]any) (*Metadata, error) {
 	}
 	metadata.Status = status
 
+	// Parse optional screenshots
+	if rawVal, ok := metadataMap["screenshots"]; ok && rawVal != nil {
+		rawScreenshots, ok := rawVal.([]any)
+		if !ok {
+			return nil, fmt.Errorf("screenshots must be an array")
+		}
+		for _, raw := range rawScreenshots {
+			name, ok := raw.(string)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("all screenshots must be non-empty strings")
+			}
+			metadata.Screenshots = append(metadata.Screenshots, name)
+		}
+	}
+
 	return metadata, nil
 }
 
@@ -176,8 +204,26 @@ func isValidStatus(status string) bool {
 	return false
 }
 
-// CreateContent generates the full file content with YAML frontmatter
+// ScreenshotEmbed returns the Obsidian embed link for a screenshot in the vault's screenshots dir
+func ScreenshotEmbed(name string) string {
+	return fmt.Sprintf("![[%s/%s]]", vault.SCREENSHOT_DIR, name)
+}
+
+// CreateContent generates the full file content with YAML frontmatter.
+// Screenshots listed in the metadata are embedded at the end of the content unless it already embeds them,
+// and metadata.Provenance is set to the content's provenance summary.
 func CreateContent(metadata *Metadata, content string) (string, error) {
+	var embeds []string
+	for _, name := range metadata.Screenshots {
+		if embed := ScreenshotEmbed(name); !strings.Contains(content, embed) {
+			embeds = append(embeds, embed)
+		}
+	}
+	if len(embeds) > 0 {
+		content = strings.TrimRight(content, "\n") + "\n\n" + strings.Join(embeds, "\n") + "\n"
+	}
+	metadata.Provenance = SummarizeProvenance(content)
+
 	// Marshal metadata to YAML
 	yamlBytes, err := yaml.Marshal(metadata)
 	if err != nil {
@@ -188,3 +234,27 @@ func CreateContent(metadata *Metadata, content string) (string, error) {
 	fileContent := fmt.Sprintf("---\n%s---\n\n%s", string(yamlBytes), content)
 	return fileContent, nil
 }
+
+// ParseContent splits a note file into its YAML frontmatter and markdown body.
+// It is the inverse of CreateContent. Notes without frontmatter return empty Metadata and the whole file as the body.
+func ParseContent(fileContent string) (*Metadata, string, error) {
+	metadata := &Metadata{}
+
+	normalized := strings.ReplaceAll(fileContent, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return metadata, fileContent, nil
+	}
+
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end == -1 {
+		return nil, "", fmt.Errorf("unterminated YAML frontmatter")
+	}
+
+	if err := yaml.Unmarshal([]byte(rest[:end]), metadata); err != nil {
+		return nil, "", fmt.Errorf("failed to parse YAML frontmatter: %w", err)
+	}
+
+	body := strings.TrimPrefix(rest[end+len("\n---"):], "\n")
+	return metadata, strings.TrimPrefix(body, "\n"), nil
+}
@@ -0,0 +1,70 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCreateContent_ScreenshotEmbeds(t *testing.T) {
+	metadata := &Metadata{Title: "Nook", Screenshots: []string{"a.png", "sub/b.png"}}
+	content := "Two tiger paintings.\n\n![[screenshots/a.png]]\n"
+
+	fileContent, err := CreateContent(metadata, content)
+	if err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	if n := strings.Count(fileContent, "![[screenshots/a.png]]"); n != 1 {
+		t.Errorf("CreateContent() embeds a.png %d times, expected once", n)
+	}
+	if !strings.HasSuffix(fileContent, "![[screenshots/a.png]]\n\n![[screenshots/sub/b.png]]\n") {
+		t.Errorf("CreateContent() should append the missing embed at the end, got %q", fileContent)
+	}
+
+	// Rewriting the note with its own body doesn't add the embeds again
+	_, body, err := ParseContent(fileContent)
+	if err != nil {
+		t.Fatalf("ParseContent() error = %v", err)
+	}
+	again, err := CreateContent(metadata, body)
+	if err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	if strings.Count(again, "![[screenshots/") != 2 {
+		t.Errorf("CreateContent() of an existing note duplicated embeds: %q", again)
+	}
+}
+
+func TestParseMetadata_PrimarySubject(t *testing.T) {
+	valid := func() map[string]any {
+		return map[string]any{
+			"title":           "Mt. Holly",
+			"category":        "people",
+			"primary_subject": "Mrs. Holly",
+			"tags":            []any{"staff"},
+			"confidence":      "high",
+			"status":          "complete",
+		}
+	}
+
+	metadata, err := ParseMetadata(valid())
+	if err != nil {
+		t.Fatalf("ParseMetadata() error = %v", err)
+	}
+	if metadata.PrimarySubject != "Mrs. Holly" {
+		t.Errorf("PrimarySubject = %q, want it read from primary_subject rather than category", metadata.PrimarySubject)
+	}
+
+	for name, value := range map[string]any{"missing": nil, "not a string": 42} {
+		t.Run(name, func(t *testing.T) {
+			m := valid()
+			if value == nil {
+				delete(m, "primary_subject")
+			} else {
+				m["primary_subject"] = value
+			}
+			if _, err := ParseMetadata(m); err == nil {
+				t.Error("ParseMetadata() should reject metadata without a string primary_subject")
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Sources of note content
+const (
+	// SourceUser is what the player said or wrote. Untagged content is the player's.
+	SourceUser = "user"
+	// SourceScreenshot is what was read off a screenshot the player took
+	SourceScreenshot = "screenshot"
+	// SourceExternal is context the assistant added from outside the player's notes
+	SourceExternal = "external"
+)
+
+// Sources lists every content source
+var Sources = []string{SourceUser, SourceScreenshot, SourceExternal}
+
+var (
+	// calloutRe matches the first line of an Obsidian callout, e.g. "> [!external] Background"
+	calloutRe = regexp.MustCompile(`^\s*>\s*\[!([A-Za-z][\w-]*)\][+-]?(.*)$`)
+	// fenceRe matches a code fence and its info string, e.g. "```source: external"
+	fenceRe = regexp.MustCompile("^\\s*(`{3,}|~{3,})\\s*(.*)$")
+	// fenceSourceRe matches the info string of a provenance fence
+	fenceSourceRe = regexp.MustCompile(`^source\s*[:=]\s*(\S*)\s*$`)
+)
+
+// ProvenanceBlock is a run of lines in a note from a single source.
+// Tagged blocks are callouts typed with their source, e.g. "> [!external]", or fences with a source info string:
+//
+//	```source: screenshot
+//	The letter reads: ...
+//	```
+type ProvenanceBlock struct {
+	Source string
+	// StartLine and EndLine are 1-based and inclusive, callout and fence markup included
+	StartLine int
+	EndLine   int
+	// Tagged is false for the player's untagged content between tagged blocks
+	Tagged bool
+}
+
+// ProvenanceIssue is a malformed provenance block
+type ProvenanceIssue struct {
+	Line    int
+	Message string
+}
+
+// Provenance summarizes how many non-blank lines of a note come from each source
+type Provenance struct {
+	User       int `json:"user,omitempty" yaml:"user,omitempty"`
+	Screenshot int `json:"screenshot,omitempty" yaml:"screenshot,omitempty"`
+	External   int `json:"external,omitempty" yaml:"external,omitempty"`
+}
+
+// ParseProvenance splits content into blocks by source. Parsing never fails: malformed blocks are reported as issues
+// and fences tagged with an unknown source are taken as external, so they are hidden rather than shown as the player's.
+func ParseProvenance(content string) ([]ProvenanceBlock, []ProvenanceIssue) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var blocks []ProvenanceBlock
+	var issues []ProvenanceIssue
+	add := func(source string, start, end int, tagged bool) {
+		// Merge runs of untagged lines
+		if n := len(blocks); !tagged && n > 0 && !blocks[n-1].Tagged && blocks[n-1].EndLine == start-1 {
+			blocks[n-1].EndLine = end
+			return
+		}
+		blocks = append(blocks, ProvenanceBlock{Source: source, StartLine: start, EndLine: end, Tagged: tagged})
+	}
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+
+		if m := fenceRe.FindStringSubmatch(lines[i]); m != nil {
+			end := closingFence(lines, i, m[1])
+			sm := fenceSourceRe.FindStringSubmatch(strings.TrimSpace(m[2]))
+			if sm == nil {
+				// An ordinary code block, which belongs to the player. Callouts inside it are not parsed.
+				add(SourceUser, lineNum, end+1, false)
+				i = end
+				continue
+			}
+
+			source := strings.ToLower(sm[1])
+			if !slices.Contains(Sources, source) {
+				issues = append(issues, ProvenanceIssue{Line: lineNum, Message: fmt.Sprintf("unknown source '%s'. Must be one of: %v", sm[1], Sources)})
+				source = SourceExternal
+			}
+			if end == len(lines)-1 && !isClosingFence(lines[end], m[1]) {
+				issues = append(issues, ProvenanceIssue{Line: lineNum, Message: fmt.Sprintf("the %s block is never closed with %s", source, m[1])})
+			}
+			add(source, lineNum, end+1, true)
+			i = end
+			continue
+		}
+
+		if m := calloutRe.FindStringSubmatch(lines[i]); m != nil && slices.Contains(Sources, strings.ToLower(m[1])) {
+			end := i
+			for end+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end+1]), ">") && !calloutRe.MatchString(lines[end+1]) {
+				end++
+			}
+			add(strings.ToLower(m[1]), lineNum, end+1, true)
+			i = end
+			continue
+		}
+
+		add(SourceUser, lineNum, lineNum, false)
+	}
+	return blocks, issues
+}
+
+// closingFence returns the index of the line closing the fence opened at lines[open], or the last line if none does
+func closingFence(lines []string, open int, marker string) int {
+	for i := open + 1; i < len(lines); i++ {
+		if isClosingFence(lines[i], marker) {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
+
+func isClosingFence(line, marker string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == ""
+}
+
+// SummarizeProvenance counts the non-blank lines of content from each source, markup excluded
+func SummarizeProvenance(content string) *Provenance {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	blocks, _ := ParseProvenance(content)
+
+	summary := &Provenance{}
+	for _, block := range blocks {
+		count := 0
+		for _, line := range blockText(lines, block) {
+			if strings.TrimSpace(line) != "" {
+				count++
+			}
+		}
+
+		switch block.Source {
+		case SourceUser:
+			summary.User += count
+		case SourceScreenshot:
+			summary.Screenshot += count
+		case SourceExternal:
+			summary.External += count
+		}
+	}
+	return summary
+}
+
+// blockText returns the lines of a block without its fence lines or callout markup. A callout's title is kept.
+func blockText(lines []string, block ProvenanceBlock) []string {
+	text := slices.Clone(lines[block.StartLine-1 : block.EndLine])
+	if !block.Tagged {
+		return text
+	}
+
+	if m := fenceRe.FindStringSubmatch(text[0]); m != nil {
+		text = text[1:]
+		if n := len(text); n > 0 && isClosingFence(text[n-1], m[1]) {
+			text = text[:n-1]
+		}
+		return text
+	}
+
+	for i, line := range text {
+		if m := calloutRe.FindStringSubmatch(line); m != nil {
+			line = m[2]
+		}
+		text[i] = strings.TrimPrefix(strings.TrimSpace(line), ">")
+	}
+	return text
+}
+
+// StripExternal returns content without its external blocks, and how many were removed
+func StripExternal(content string) (string, int) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	blocks, _ := ParseProvenance(content)
+
+	var kept []string
+	removed := 0
+	for _, block := range blocks {
+		if block.Source == SourceExternal {
+			removed++
+			continue
+		}
+		kept = append(kept, lines[block.StartLine-1:block.EndLine]...)
+	}
+	return strings.Join(kept, "\n"), removed
+}
@@ -0,0 +1,118 @@
+package notes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProvenance(t *testing.T) {
+	content := strings.Join([]string{
+		"Tiger paintings in the Nook.", // 1
+		"",
+		"> [!external] Background", // 3
+		"> The painter is famous.",
+		"> So is the frame.",
+		"",
+		"```source: screenshot", // 7
+		"The letter reads: see you at noon",
+		"```",
+		"```go", // 10
+		"> [!external] not a callout inside a code block",
+		"```",
+		"> [!note] A callout of another type is the player's", // 13
+	}, "\n")
+
+	blocks, issues := ParseProvenance(content)
+	want := []ProvenanceBlock{
+		{Source: SourceUser, StartLine: 1, EndLine: 2},
+		{Source: SourceExternal, StartLine: 3, EndLine: 5, Tagged: true},
+		{Source: SourceUser, StartLine: 6, EndLine: 6},
+		{Source: SourceScreenshot, StartLine: 7, EndLine: 9, Tagged: true},
+		{Source: SourceUser, StartLine: 10, EndLine: 13},
+	}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("ParseProvenance() blocks = %+v, want %+v", blocks, want)
+	}
+	if len(issues) != 0 {
+		t.Errorf("ParseProvenance() issues = %+v, want none", issues)
+	}
+
+	// The callout title counts, its "> [!external]" markup and the screenshot fence lines don't.
+	// The ordinary code block is counted whole, as the player wrote it.
+	got := SummarizeProvenance(content)
+	if wantSummary := (&Provenance{User: 5, Screenshot: 1, External: 3}); *got != *wantSummary {
+		t.Errorf("SummarizeProvenance() = %+v, want %+v", got, wantSummary)
+	}
+}
+
+func TestParseProvenance_Issues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []ProvenanceBlock
+		issue   string
+	}{
+		{
+			name:    "unknown source",
+			content: "Mine.\n```source: wiki\nFrom somewhere.\n```",
+			want: []ProvenanceBlock{
+				{Source: SourceUser, StartLine: 1, EndLine: 1},
+				{Source: SourceExternal, StartLine: 2, EndLine: 4, Tagged: true},
+			},
+			issue: "unknown source 'wiki'",
+		},
+		{
+			name:    "unclosed fence",
+			content: "Mine.\n~~~source = external\nEverything after it.\nIs hidden.",
+			want: []ProvenanceBlock{
+				{Source: SourceUser, StartLine: 1, EndLine: 1},
+				{Source: SourceExternal, StartLine: 2, EndLine: 4, Tagged: true},
+			},
+			issue: "never closed with ~~~",
+		},
+		{
+			name:    "unclosed ordinary fence",
+			content: "```\n> [!external] still code",
+			want:    []ProvenanceBlock{{Source: SourceUser, StartLine: 1, EndLine: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, issues := ParseProvenance(tt.content)
+			if !reflect.DeepEqual(blocks, tt.want) {
+				t.Errorf("ParseProvenance() blocks = %+v, want %+v", blocks, tt.want)
+			}
+			switch {
+			case tt.issue == "" && len(issues) > 0:
+				t.Errorf("ParseProvenance() issues = %+v, want none", issues)
+			case tt.issue != "" && (len(issues) != 1 || issues[0].Line != 2 || !strings.Contains(issues[0].Message, tt.issue)):
+				t.Errorf("ParseProvenance() issues = %+v, want one on line 2 containing %q", issues, tt.issue)
+			}
+		})
+	}
+}
+
+func TestStripExternal(t *testing.T) {
+	content := "---\ntitle: Nook\ntags: [nook]\n---\n\nMine.\n\n> [!external] Background\n> Theirs.\n\n```source: external\nAlso theirs.\n```\n\n```source: screenshot\nRead off the wall.\n```\nMore mine.\n"
+
+	stripped, removed := StripExternal(content)
+	want := "---\ntitle: Nook\ntags: [nook]\n---\n\nMine.\n\n\n\n```source: screenshot\nRead off the wall.\n```\nMore mine.\n"
+	if stripped != want || removed != 2 {
+		t.Errorf("StripExternal() = %q, %d, want %q, 2", stripped, removed, want)
+	}
+
+	// The frontmatter survives intact
+	metadata, body, err := ParseContent(stripped)
+	if err != nil {
+		t.Fatalf("ParseContent() error = %v", err)
+	}
+	if metadata.Title != "Nook" || strings.Contains(body, "heirs") {
+		t.Errorf("ParseContent() = %+v, %q", metadata, body)
+	}
+
+	if stripped, removed := StripExternal("Only mine."); stripped != "Only mine." || removed != 0 {
+		t.Errorf("StripExternal() = %q, %d, want the content unchanged", stripped, removed)
+	}
+}
@@ -0,0 +1,456 @@
+package screenshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/ocr"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// DefaultSimilarityDistance is the max number of differing dHash bits for two screenshots to count as near-duplicates
+const DefaultSimilarityDistance = 10
+
+// manifestMu serializes read-modify-write cycles on the manifest file across tool handlers
+var manifestMu sync.Mutex
+
+// Sources a screenshot can be imported from
+const (
+	SourceGoogleDrive = "google_drive"
+	SourceLocalStore  = "local_store"
+	// SourceVault marks screenshots that were copied into the vault by hand and picked up by Index
+	SourceVault = "vault"
+)
+
+// Where an entry's CapturedAt was read from, most reliable first
+const (
+	CaptureSourceExif     = "exif"
+	CaptureSourceFilename = "filename"
+	// CaptureSourceImport is the time reported when the file was imported, e.g. its Drive upload or file modification time
+	CaptureSourceImport = "import"
+)
+
+// Analysis statuses of a screenshot
+const (
+	// StatusNew screenshots have not been analyzed yet
+	StatusNew = "new"
+	// StatusAnalyzed screenshots have been analyzed but no note embeds them yet
+	StatusAnalyzed = "analyzed"
+	// StatusNoted screenshots are embedded in at least one note
+	StatusNoted = "noted"
+	// StatusSkipped screenshots were deliberately left out of the notes
+	StatusSkipped = "skipped"
+)
+
+// Entry records everything known about a single screenshot in the vault
+type Entry struct {
+	Name       string `json:"name"`
+	SHA256     string `json:"sha256"`
+	DHash      string `json:"dhash,omitempty"`
+	ImportedAt string `json:"imported_at"`
+	// Source is where the screenshot was imported from, one of the Source constants
+	Source string `json:"source,omitempty"`
+	// CapturedAt is the best known time the screenshot was taken
+	CapturedAt string `json:"captured_at,omitempty"`
+	// CaptureSource is where CapturedAt was read from, one of the CaptureSource constants
+	CaptureSource string `json:"capture_source,omitempty"`
+	// Status is how far the screenshot is through the analysis workflow, one of the Status constants
+	Status string `json:"status,omitempty"`
+	// Notes are the paths, relative to the notes dir, of notes that embed the screenshot
+	Notes []string `json:"notes,omitempty"`
+	// OCR holds the text recognized in the screenshot, keyed by the area that was read (see OCRFullArea)
+	OCR map[string]*ocr.Result `json:"ocr,omitempty"`
+}
+
+// Origin describes where an imported screenshot came from
+type Origin struct {
+	Source     string
+	CapturedAt time.Time
+}
+
+// Manifest tracks every screenshot that has entered the vault's screenshots dir, keyed by file name
+type Manifest struct {
+	Entries map[string]*Entry `json:"entries"`
+
+	path string
+}
+
+// Match is a screenshot whose perceptual hash is within some distance of another
+type Match struct {
+	Name     string `json:"name"`
+	Distance int    `json:"distance"`
+}
+
+// ManifestPath returns the location of the screenshot manifest within the vault
+func ManifestPath(vaultPath string) string {
+	return filepath.Join(vaultPath, vault.META_DIR, vault.SCREENSHOT_MANIFEST)
+}
+
+// LoadManifest reads the screenshot manifest from the vault's meta dir.
+// A missing manifest is not an error; an empty one is returned instead.
+func LoadManifest(vaultPath string) (*Manifest, error) {
+	m := &Manifest{
+		Entries: make(map[string]*Entry),
+		path:    ManifestPath(vaultPath),
+	}
+
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read screenshot manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse screenshot manifest: %w", err)
+	}
+	if m.Entries == nil {
+		m.Entries = make(map[string]*Entry)
+	}
+	return m, nil
+}
+
+// Save writes the manifest back to the vault's meta dir
+func (m *Manifest) Save() error {
+	if err := utils.EnsureDirExists(filepath.Dir(m.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal screenshot manifest: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated manifest behind
+	tmpPath := m.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write screenshot manifest: %w", err)
+	}
+	if err := os.Rename(tmpPath, m.path); err != nil {
+		return fmt.Errorf("failed to replace screenshot manifest: %w", err)
+	}
+	return nil
+}
+
+// Update loads the manifest, applies fn and saves the result while holding the manifest lock
+func Update(vaultPath string, fn func(m *Manifest) error) error {
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+
+	m, err := LoadManifest(vaultPath)
+	if err != nil {
+		return err
+	}
+	if err := fn(m); err != nil {
+		return err
+	}
+	return m.Save()
+}
+
+// FindBySHA256 returns the entry with the given content hash, or nil if none exists
+func (m *Manifest) FindBySHA256(sum string) *Entry {
+	for _, entry := range m.Entries {
+		if entry.SHA256 == sum {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Add hashes the image data and records it under name as a new screenshot.
+// Images that cannot be decoded are still tracked by SHA-256 but get no perceptual hash.
+func (m *Manifest) Add(name string, data []byte, origin Origin) *Entry {
+	entry := &Entry{
+		Name:       name,
+		SHA256:     utils.HashBytes(data),
+		ImportedAt: time.Now().Format(time.RFC3339),
+		Source:     origin.Source,
+		Status:     StatusNew,
+	}
+	entry.setCaptureTime(data, origin.CapturedAt)
+	if dhash, err := utils.PerceptualHash(data); err == nil {
+		entry.DHash = dhash
+	}
+	m.Entries[name] = entry
+	return entry
+}
+
+// setCaptureTime records when the screenshot was taken, preferring its EXIF data, then its file name, then fallback
+func (e *Entry) setCaptureTime(data []byte, fallback time.Time) {
+	if t, ok := utils.ExifCaptureTime(data); ok {
+		e.CapturedAt, e.CaptureSource = t.Format(time.RFC3339), CaptureSourceExif
+		return
+	}
+	if t, ok := utils.FilenameCaptureTime(e.Name); ok {
+		e.CapturedAt, e.CaptureSource = t.Format(time.RFC3339), CaptureSourceFilename
+		return
+	}
+	if !fallback.IsZero() {
+		e.CapturedAt = fallback.Format(time.RFC3339)
+	}
+	e.CaptureSource = CaptureSourceImport
+}
+
+// FilePath validates a screenshot name and returns the full path of the screenshot in the vault's screenshots dir.
+// Returns an error if the name escapes the dir or no such screenshot exists.
+func FilePath(vaultPath, name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("file_name is required")
+	}
+
+	cleanName, err := utils.ValidatePath(name)
+	if err != nil {
+		return "", err
+	}
+	fullPath, err := utils.BuildSecurePath(vaultPath, vault.SCREENSHOT_DIR, cleanName)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("screenshot not found: '%s'", name)
+	}
+	return fullPath, nil
+}
+
+// Import writes data into the vault's screenshots dir and records it in the manifest.
+// It returns the name the screenshot was saved under: name, or name with a content hash suffix if a different
+// screenshot already has that name, so an existing screenshot and its status, note links and OCR text are never replaced.
+// Returns an empty name without writing anything if identical content has already been imported.
+func Import(vaultPath, name string, data []byte, origin Origin) (string, error) {
+	if _, err := utils.BuildSecurePath(vaultPath, vault.SCREENSHOT_DIR, name); err != nil {
+		return "", fmt.Errorf("Security validation failed for img path %q: %w", name, err)
+	}
+
+	imported := ""
+	err := Update(vaultPath, func(m *Manifest) error {
+		// Drop entries whose file was deleted, so a screenshot removed from the vault can be downloaded again
+		if _, err := m.Index(filepath.Join(vaultPath, vault.SCREENSHOT_DIR)); err != nil {
+			return err
+		}
+		sum := utils.HashBytes(data)
+		if dup := m.FindBySHA256(sum); dup != nil {
+			return nil
+		}
+
+		// Index tracks every file in the dir, so a name without an entry is free
+		saveAs := m.uniqueName(name, sum)
+		fullPath, err := utils.BuildSecurePath(vaultPath, vault.SCREENSHOT_DIR, saveAs)
+		if err != nil {
+			return fmt.Errorf("Security validation failed for img path %q: %w", saveAs, err)
+		}
+		if err := utils.EnsureDirExists(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to create local file '%s': %w", fullPath, err)
+		}
+		m.Add(saveAs, data, origin)
+		imported = saveAs
+		return nil
+	})
+	return imported, err
+}
+
+// uniqueName returns name if no screenshot has it yet, or else name with as much of sum as it takes to be unique
+// inserted before the extension, e.g. "foyer_1a2b3c4d.png"
+func (m *Manifest) uniqueName(name, sum string) string {
+	if _, taken := m.Entries[name]; !taken {
+		return name
+	}
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for n := 8; n < len(sum); n += 8 {
+		candidate := fmt.Sprintf("%s_%s%s", stem, sum[:n], ext)
+		if _, taken := m.Entries[candidate]; !taken {
+			return candidate
+		}
+	}
+	return fmt.Sprintf("%s_%s%s", stem, sum, ext)
+}
+
+// Index brings the manifest in line with the contents of the screenshots dir.
+// Files that are not yet tracked (e.g. copied in by hand) are hashed and added, and entries whose file is gone are dropped.
+// Returns the names of newly indexed files.
+func (m *Manifest) Index(screenshotsDir string) ([]string, error) {
+	if err := utils.EnsureDirExists(screenshotsDir, 0755); err != nil {
+		return nil, err
+	}
+	files, err := utils.ListFiles(screenshotsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	present := make(map[string]bool, len(files))
+	var added []string
+	for _, name := range files {
+		name = filepath.ToSlash(name)
+		present[name] = true
+		fullPath := filepath.Join(screenshotsDir, name)
+		if entry, ok := m.Entries[name]; ok {
+			// Entries recorded before capture times were parsed out of the file get them once
+			if entry.CaptureSource == "" {
+				data, err := os.ReadFile(fullPath)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read screenshot '%s': %w", name, err)
+				}
+				fallback, _ := time.Parse(time.RFC3339, entry.CapturedAt)
+				entry.setCaptureTime(data, fallback)
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read screenshot '%s': %w", name, err)
+		}
+		origin := Origin{Source: SourceVault}
+		if info, err := os.Stat(fullPath); err == nil {
+			origin.CapturedAt = info.ModTime()
+		}
+		m.Add(name, data, origin)
+		added = append(added, name)
+	}
+
+	for name := range m.Entries {
+		if !present[name] {
+			delete(m.Entries, name)
+		}
+	}
+	return added, nil
+}
+
+// SetNoteLinks records that the note at notePath embeds exactly the named screenshots.
+// The note is unlinked from any other screenshot, and linked screenshots are marked as noted.
+// A noted screenshot that loses its last note goes back to analyzed so it is picked up by the queue again.
+func (m *Manifest) SetNoteLinks(notePath string, names []string) error {
+	linked := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, ok := m.Entries[name]; !ok {
+			return fmt.Errorf("screenshot '%s' is not in the vault", name)
+		}
+		linked[name] = true
+	}
+
+	for name, entry := range m.Entries {
+		notes := removeString(entry.Notes, notePath)
+		if linked[name] {
+			notes = append(notes, notePath)
+			sort.Strings(notes)
+			entry.Status = StatusNoted
+		} else if len(notes) == 0 && entry.Status == StatusNoted {
+			entry.Status = StatusAnalyzed
+		}
+		entry.Notes = notes
+	}
+	return nil
+}
+
+// LinkNote indexes the screenshots dir and records that the note at notePath embeds the named screenshots
+func LinkNote(vaultPath, notePath string, names []string) error {
+	return Update(vaultPath, func(m *Manifest) error {
+		if _, err := m.Index(filepath.Join(vaultPath, vault.SCREENSHOT_DIR)); err != nil {
+			return err
+		}
+		return m.SetNoteLinks(notePath, names)
+	})
+}
+
+// Similar returns the screenshots whose perceptual hash is within maxDistance bits of the named screenshot,
+// closest first. The named screenshot itself is excluded.
+func (m *Manifest) Similar(name string, maxDistance int) ([]Match, error) {
+	target, ok := m.Entries[name]
+	if !ok {
+		return nil, fmt.Errorf("screenshot '%s' is not in the manifest", name)
+	}
+	if target.DHash == "" {
+		return nil, fmt.Errorf("screenshot '%s' has no perceptual hash", name)
+	}
+	targetHash, err := utils.ParseDHash(target.DHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, entry := range m.Entries {
+		if entry.Name == name || entry.DHash == "" {
+			continue
+		}
+		hash, err := utils.ParseDHash(entry.DHash)
+		if err != nil {
+			continue
+		}
+		if distance := utils.HammingDistance(targetHash, hash); distance <= maxDistance {
+			matches = append(matches, Match{Name: entry.Name, Distance: distance})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches, nil
+}
+
+// SimilarGroups clusters all screenshots into groups of near-duplicates.
+// Only groups with more than one screenshot are returned, each sorted by name.
+func (m *Manifest) SimilarGroups(maxDistance int) [][]string {
+	names := make([]string, 0, len(m.Entries))
+	hashes := make(map[string]uint64, len(m.Entries))
+	for name, entry := range m.Entries {
+		hash, err := utils.ParseDHash(entry.DHash)
+		if err != nil {
+			continue
+		}
+		names = append(names, name)
+		hashes[name] = hash
+	}
+	sort.Strings(names)
+
+	// Single-linkage clustering: any two screenshots within maxDistance end up in the same group
+	grouped := make(map[string]bool, len(names))
+	var groups [][]string
+	for _, name := range names {
+		if grouped[name] {
+			continue
+		}
+		group := []string{name}
+		grouped[name] = true
+		for i := 0; i < len(group); i++ {
+			for _, other := range names {
+				if grouped[other] {
+					continue
+				}
+				if utils.HammingDistance(hashes[group[i]], hashes[other]) <= maxDistance {
+					group = append(group, other)
+					grouped[other] = true
+				}
+			}
+		}
+		if len(group) > 1 {
+			sort.Strings(group)
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+func removeString(values []string, target string) []string {
+	var out []string
+	for _, v := range values {
+		if v != target {
+			out = append(out, v)
+		}
+	}
+	return out
+}
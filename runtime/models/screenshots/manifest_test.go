@@ -0,0 +1,162 @@
+package screenshots
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+)
+
+func TestImport(t *testing.T) {
+	vaultPath := t.TempDir()
+	data := []byte("not really a png")
+	origin := Origin{Source: SourceLocalStore}
+
+	saved, err := Import(vaultPath, "a.png", data, origin)
+	if err != nil || saved != "a.png" {
+		t.Fatalf("Import() = %q, %v, expected a new import as a.png", saved, err)
+	}
+
+	// Identical content under another name is a duplicate
+	saved, err = Import(vaultPath, "b.png", data, origin)
+	if err != nil || saved != "" {
+		t.Fatalf("Import() of a duplicate = %q, %v, expected it to be skipped", saved, err)
+	}
+	if _, err := os.Stat(filepath.Join(vaultPath, vault.SCREENSHOT_DIR, "b.png")); !os.IsNotExist(err) {
+		t.Error("Import() should not write a duplicate")
+	}
+
+	// Once the player deletes the file, its stale entry must not block downloading it again
+	if err := os.Remove(filepath.Join(vaultPath, vault.SCREENSHOT_DIR, "a.png")); err != nil {
+		t.Fatal(err)
+	}
+	saved, err = Import(vaultPath, "a.png", data, origin)
+	if err != nil || saved != "a.png" {
+		t.Fatalf("Import() after deleting the file = %q, %v, expected a new import as a.png", saved, err)
+	}
+	if _, err := os.Stat(filepath.Join(vaultPath, vault.SCREENSHOT_DIR, "a.png")); err != nil {
+		t.Errorf("Import() should write the file again: %v", err)
+	}
+
+	m, err := LoadManifest(vaultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Entries) != 1 || m.Entries["a.png"] == nil || m.Entries["a.png"].Status != StatusNew {
+		t.Errorf("manifest entries = %v, expected only a.png as new", m.Entries)
+	}
+}
+
+func TestImport_SameNameDifferentContent(t *testing.T) {
+	vaultPath := t.TempDir()
+	original := []byte("the foyer")
+	if _, err := Import(vaultPath, "foyer.png", original, Origin{Source: SourceLocalStore}); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkNote(vaultPath, "rooms/foyer.md", []string{"foyer.png"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another screenshot with the same name is saved alongside instead of replacing the first
+	other := []byte("a different foyer")
+	saved, err := Import(vaultPath, "foyer.png", other, Origin{Source: SourceLocalStore})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if saved == "foyer.png" || !strings.HasPrefix(saved, "foyer_") || filepath.Ext(saved) != ".png" {
+		t.Fatalf("Import() saved as %q, expected foyer_<hash>.png", saved)
+	}
+
+	for name, want := range map[string][]byte{"foyer.png": original, saved: other} {
+		got, err := os.ReadFile(filepath.Join(vaultPath, vault.SCREENSHOT_DIR, name))
+		if err != nil || string(got) != string(want) {
+			t.Errorf("%s = %q, %v, expected %q", name, got, err, want)
+		}
+	}
+
+	m, err := LoadManifest(vaultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := m.Entries["foyer.png"]; entry == nil || entry.Status != StatusNoted || len(entry.Notes) != 1 {
+		t.Errorf("foyer.png = %+v, expected it to keep its note link", entry)
+	}
+	if entry := m.Entries[saved]; entry == nil || entry.Status != StatusNew {
+		t.Errorf("%s = %+v, expected a new entry", saved, entry)
+	}
+}
+
+// writeScreenshot puts a screenshot file into the vault by hand, as a player copying it in would
+func writeScreenshot(t *testing.T, vaultPath, name string) {
+	t.Helper()
+	dir := filepath.Join(vaultPath, vault.SCREENSHOT_DIR)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("screenshot "+name), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLinkNote(t *testing.T) {
+	vaultPath := t.TempDir()
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		writeScreenshot(t, vaultPath, name)
+	}
+
+	check := func(step string, expected map[string]string) {
+		t.Helper()
+		m, err := LoadManifest(vaultPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, want := range expected {
+			entry := m.Entries[name]
+			if entry == nil {
+				t.Fatalf("%s: %s is not in the manifest", step, name)
+			}
+			if got := entry.CurrentStatus() + " " + strings.Join(entry.Notes, ","); got != want {
+				t.Errorf("%s: %s = %q, expected %q", step, name, got, want)
+			}
+		}
+	}
+
+	// create_note links the note's screenshots
+	if err := LinkNote(vaultPath, "rooms/nook.md", []string{"a.png", "b.png"}); err != nil {
+		t.Fatalf("LinkNote() error = %v", err)
+	}
+	if err := LinkNote(vaultPath, "people/simon.md", []string{"b.png"}); err != nil {
+		t.Fatalf("LinkNote() error = %v", err)
+	}
+	check("link", map[string]string{
+		"a.png": "noted rooms/nook.md",
+		"b.png": "noted people/simon.md,rooms/nook.md",
+		"c.png": "new ",
+	})
+
+	// update_note relinks: a.png loses its only note and goes back to analyzed, c.png is added
+	if err := LinkNote(vaultPath, "rooms/nook.md", []string{"b.png", "c.png"}); err != nil {
+		t.Fatalf("LinkNote() error = %v", err)
+	}
+	check("relink", map[string]string{
+		"a.png": "analyzed ",
+		"b.png": "noted people/simon.md,rooms/nook.md",
+		"c.png": "noted rooms/nook.md",
+	})
+
+	// delete_note unlinks everything; b.png is still embedded in the other note
+	if err := LinkNote(vaultPath, "rooms/nook.md", nil); err != nil {
+		t.Fatalf("LinkNote() error = %v", err)
+	}
+	check("unlink", map[string]string{
+		"a.png": "analyzed ",
+		"b.png": "noted people/simon.md",
+		"c.png": "analyzed ",
+	})
+
+	if err := LinkNote(vaultPath, "rooms/nook.md", []string{"missing.png"}); err == nil {
+		t.Error("LinkNote() should fail for a screenshot that isn't in the vault")
+	}
+}
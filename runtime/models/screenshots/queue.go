@@ -0,0 +1,144 @@
+package screenshots
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+)
+
+// Statuses lists every analysis status in workflow order
+var Statuses = []string{StatusNew, StatusAnalyzed, StatusNoted, StatusSkipped}
+
+// CurrentStatus returns the screenshot's status. Entries recorded before statuses were tracked count as new.
+func (e *Entry) CurrentStatus() string {
+	if e.Status == "" {
+		return StatusNew
+	}
+	return e.Status
+}
+
+// Pending reports whether the screenshot still needs to go through the analyze -> create_note workflow.
+// Analyzed screenshots stay pending until a note embeds them, since the analysis itself only lives in a conversation.
+func (e *Entry) Pending() bool {
+	status := e.CurrentStatus()
+	return status == StatusNew || status == StatusAnalyzed
+}
+
+// Queue returns the pending screenshots, oldest capture first
+func (m *Manifest) Queue() []*Entry {
+	var queue []*Entry
+	for _, entry := range m.Entries {
+		if entry.Pending() {
+			queue = append(queue, entry)
+		}
+	}
+
+	sort.Slice(queue, func(i, j int) bool {
+		a, b := queue[i], queue[j]
+		if ta, tb := a.CaptureTime(), b.CaptureTime(); !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		if ta, tb := parseTime(a.ImportedAt), parseTime(b.ImportedAt); !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return a.Name < b.Name
+	})
+	return queue
+}
+
+// parseTime parses an RFC3339 manifest timestamp. Missing or malformed ones are the zero time and sort first.
+func parseTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
+}
+
+// StatusCounts returns how many screenshots are in each status
+func (m *Manifest) StatusCounts() map[string]int {
+	counts := make(map[string]int, len(Statuses))
+	for _, entry := range m.Entries {
+		counts[entry.CurrentStatus()]++
+	}
+	return counts
+}
+
+// FormatStatusCounts summarizes how many screenshots are in each status, in workflow order
+func FormatStatusCounts(counts map[string]int) string {
+	parts := make([]string, len(Statuses))
+	for i, status := range Statuses {
+		parts[i] = fmt.Sprintf("%s: %d", status, counts[status])
+	}
+	return "Screenshots by status: " + strings.Join(parts, ", ")
+}
+
+// SetStatus moves the named screenshot to status.
+// Noted is reserved for screenshots embedded in a note, so it can only be set through SetNoteLinks.
+func (m *Manifest) SetStatus(name, status string) error {
+	entry, ok := m.Entries[name]
+	if !ok {
+		return fmt.Errorf("screenshot '%s' is not in the vault", name)
+	}
+
+	switch status {
+	case StatusNew, StatusAnalyzed, StatusSkipped:
+	case StatusNoted:
+		return fmt.Errorf("status '%s' is set by listing the screenshot in a note's metadata.screenshots", StatusNoted)
+	default:
+		return fmt.Errorf("invalid status '%s'. Must be one of: %v", status, Statuses)
+	}
+	if len(entry.Notes) > 0 {
+		return fmt.Errorf("screenshot '%s' is embedded in notes %v", name, entry.Notes)
+	}
+
+	entry.Status = status
+	return nil
+}
+
+// NextInQueue indexes the screenshots dir and returns the next screenshot to analyze along with the manifest it came from.
+// The entry is nil when the queue is empty.
+func NextInQueue(vaultPath string) (*Entry, *Manifest, error) {
+	var next *Entry
+	var manifest *Manifest
+	err := Update(vaultPath, func(m *Manifest) error {
+		if _, err := m.Index(filepath.Join(vaultPath, vault.SCREENSHOT_DIR)); err != nil {
+			return err
+		}
+		if queue := m.Queue(); len(queue) > 0 {
+			next = queue[0]
+		}
+		manifest = m
+		return nil
+	})
+	return next, manifest, err
+}
+
+// SetStatus indexes the screenshots dir and moves the named screenshot to status
+func SetStatus(vaultPath, name, status string) error {
+	return Update(vaultPath, func(m *Manifest) error {
+		if _, err := m.Index(filepath.Join(vaultPath, vault.SCREENSHOT_DIR)); err != nil {
+			return err
+		}
+		return m.SetStatus(name, status)
+	})
+}
+
+// MarkAnalyzed records that the named screenshot has been analyzed.
+// Screenshots that are already noted or skipped keep their status.
+func MarkAnalyzed(vaultPath, name string) error {
+	return Update(vaultPath, func(m *Manifest) error {
+		if _, err := m.Index(filepath.Join(vaultPath, vault.SCREENSHOT_DIR)); err != nil {
+			return err
+		}
+		entry, ok := m.Entries[name]
+		if !ok {
+			return fmt.Errorf("screenshot '%s' is not in the vault", name)
+		}
+		if entry.CurrentStatus() == StatusNew {
+			entry.Status = StatusAnalyzed
+		}
+		return nil
+	})
+}
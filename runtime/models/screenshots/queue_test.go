@@ -0,0 +1,211 @@
+package screenshots
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// seedManifest writes a file for each entry into the vault's screenshots dir and records the entries as given,
+// so tests can start from statuses and capture times that would otherwise take a whole workflow to reach
+func seedManifest(t *testing.T, vaultPath string, entries ...*Entry) {
+	t.Helper()
+	for _, entry := range entries {
+		writeScreenshot(t, vaultPath, entry.Name)
+		if entry.CaptureSource == "" {
+			// Keep Index from parsing a capture time out of the file
+			entry.CaptureSource = CaptureSourceImport
+		}
+	}
+	err := Update(vaultPath, func(m *Manifest) error {
+		for _, entry := range entries {
+			m.Entries[entry.Name] = entry
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func names(entries []*Entry) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Name
+	}
+	return result
+}
+
+func TestQueue(t *testing.T) {
+	vaultPath := t.TempDir()
+	seedManifest(t, vaultPath,
+		&Entry{Name: "legacy.png", CapturedAt: "2025-04-15T19:00:00Z"},
+		&Entry{Name: "new.png", Status: StatusNew, CapturedAt: "2025-04-15T18:00:00Z"},
+		&Entry{Name: "analyzed.png", Status: StatusAnalyzed, CapturedAt: "2025-04-15T20:00:00Z"},
+		&Entry{Name: "noted.png", Status: StatusNoted, CapturedAt: "2025-04-15T17:00:00Z", Notes: []string{"rooms/nook.md"}},
+		&Entry{Name: "skipped.png", Status: StatusSkipped, CapturedAt: "2025-04-15T16:00:00Z"},
+		// No capture time sorts first, then ties fall back to the import time and the name
+		&Entry{Name: "b-undated.png", Status: StatusNew, ImportedAt: "2025-04-16T10:00:00Z"},
+		&Entry{Name: "a-undated.png", Status: StatusNew, ImportedAt: "2025-04-16T10:00:00Z"},
+		&Entry{Name: "c-undated.png", Status: StatusNew, ImportedAt: "2025-04-16T09:00:00Z"},
+	)
+
+	m, err := LoadManifest(vaultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"c-undated.png", "a-undated.png", "b-undated.png", "new.png", "legacy.png", "analyzed.png"}
+	if got := names(m.Queue()); !slices.Equal(got, want) {
+		t.Errorf("Queue() = %v, want %v", got, want)
+	}
+
+	counts := m.StatusCounts()
+	wantCounts := map[string]int{StatusNew: 5, StatusAnalyzed: 1, StatusNoted: 1, StatusSkipped: 1}
+	for _, status := range Statuses {
+		if counts[status] != wantCounts[status] {
+			t.Errorf("StatusCounts()[%s] = %d, want %d", status, counts[status], wantCounts[status])
+		}
+	}
+	if _, ok := counts[""]; ok {
+		t.Error("StatusCounts() should count legacy entries as new")
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   Entry
+		status  string
+		want    string
+		wantErr bool
+	}{
+		{name: "new to analyzed", entry: Entry{Status: StatusNew}, status: StatusAnalyzed, want: StatusAnalyzed},
+		{name: "new to skipped", entry: Entry{Status: StatusNew}, status: StatusSkipped, want: StatusSkipped},
+		{name: "analyzed to skipped", entry: Entry{Status: StatusAnalyzed}, status: StatusSkipped, want: StatusSkipped},
+		{name: "skipped back to new", entry: Entry{Status: StatusSkipped}, status: StatusNew, want: StatusNew},
+		{name: "legacy to skipped", entry: Entry{}, status: StatusSkipped, want: StatusSkipped},
+		{name: "noted is set by notes", entry: Entry{Status: StatusAnalyzed}, status: StatusNoted, wantErr: true},
+		{name: "invalid status", entry: Entry{Status: StatusNew}, status: "done", wantErr: true},
+		{name: "embedded in a note", entry: Entry{Status: StatusNoted, Notes: []string{"rooms/nook.md"}}, status: StatusSkipped, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultPath := t.TempDir()
+			entry := tt.entry
+			entry.Name = "a.png"
+			seedManifest(t, vaultPath, &entry)
+
+			err := SetStatus(vaultPath, "a.png", tt.status)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			m, err := LoadManifest(vaultPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.want
+			if tt.wantErr {
+				want = tt.entry.CurrentStatus()
+			}
+			if got := m.Entries["a.png"].CurrentStatus(); got != want {
+				t.Errorf("status = %q, want %q", got, want)
+			}
+		})
+	}
+
+	if err := SetStatus(t.TempDir(), "missing.png", StatusSkipped); err == nil {
+		t.Error("SetStatus() should fail for a screenshot that isn't in the vault")
+	}
+}
+
+func TestMarkAnalyzed(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		want  string
+	}{
+		{name: "new", entry: Entry{Status: StatusNew}, want: StatusAnalyzed},
+		{name: "legacy", entry: Entry{}, want: StatusAnalyzed},
+		{name: "analyzed", entry: Entry{Status: StatusAnalyzed}, want: StatusAnalyzed},
+		{name: "noted", entry: Entry{Status: StatusNoted, Notes: []string{"rooms/nook.md"}}, want: StatusNoted},
+		{name: "skipped", entry: Entry{Status: StatusSkipped}, want: StatusSkipped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultPath := t.TempDir()
+			entry := tt.entry
+			entry.Name = "a.png"
+			seedManifest(t, vaultPath, &entry)
+
+			if err := MarkAnalyzed(vaultPath, "a.png"); err != nil {
+				t.Fatalf("MarkAnalyzed() error = %v", err)
+			}
+			m, err := LoadManifest(vaultPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := m.Entries["a.png"].Status; got != tt.want {
+				t.Errorf("status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := MarkAnalyzed(t.TempDir(), "missing.png"); err == nil {
+		t.Error("MarkAnalyzed() should fail for a screenshot that isn't in the vault")
+	}
+}
+
+func TestNextInQueue(t *testing.T) {
+	vaultPath := t.TempDir()
+	start := time.Date(2025, 4, 15, 18, 0, 0, 0, time.UTC)
+	seedManifest(t, vaultPath,
+		&Entry{Name: "first.png", CapturedAt: start.Format(time.RFC3339)},
+		&Entry{Name: "second.png", Status: StatusNew, CapturedAt: start.Add(time.Minute).Format(time.RFC3339)},
+		&Entry{Name: "third.png", Status: StatusNew, CapturedAt: start.Add(2 * time.Minute).Format(time.RFC3339)},
+	)
+
+	next := func(step, want string) {
+		t.Helper()
+		entry, m, err := NextInQueue(vaultPath)
+		if err != nil {
+			t.Fatalf("%s: NextInQueue() error = %v", step, err)
+		}
+		if m == nil {
+			t.Fatalf("%s: NextInQueue() returned no manifest", step)
+		}
+		got := ""
+		if entry != nil {
+			got = entry.Name
+		}
+		if got != want {
+			t.Errorf("%s: NextInQueue() = %q, want %q", step, got, want)
+		}
+	}
+
+	next("start", "first.png")
+
+	// Analyzed screenshots stay at the front until a note embeds them
+	if err := MarkAnalyzed(vaultPath, "first.png"); err != nil {
+		t.Fatal(err)
+	}
+	next("analyzed", "first.png")
+
+	if err := LinkNote(vaultPath, "rooms/nook.md", []string{"first.png"}); err != nil {
+		t.Fatal(err)
+	}
+	next("noted", "second.png")
+
+	if err := SetStatus(vaultPath, "second.png", StatusSkipped); err != nil {
+		t.Fatal(err)
+	}
+	next("skipped", "third.png")
+
+	if err := SetStatus(vaultPath, "third.png", StatusSkipped); err != nil {
+		t.Fatal(err)
+	}
+	next("empty", "")
+}
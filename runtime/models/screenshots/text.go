@@ -0,0 +1,76 @@
+package screenshots
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/ocr"
+)
+
+// OCRFullArea is the OCR key of text read from the whole screenshot. Other areas are keyed by region name or rectangle.
+const OCRFullArea = "full"
+
+// TextMatch is a screenshot whose recognized text contains a search query
+type TextMatch struct {
+	Name  string
+	Notes []string
+	// Lines are the matching lines, prefixed by the area they were read from
+	Lines []string
+}
+
+// SetOCR stores the text recognized in an area of the named screenshot, replacing any earlier result for that area
+func SetOCR(vaultPath, name, area string, result *ocr.Result) error {
+	return Update(vaultPath, func(m *Manifest) error {
+		if _, err := m.Index(filepath.Join(vaultPath, vault.SCREENSHOT_DIR)); err != nil {
+			return err
+		}
+		entry, ok := m.Entries[name]
+		if !ok {
+			return fmt.Errorf("screenshot '%s' is not in the vault", name)
+		}
+		if entry.OCR == nil {
+			entry.OCR = make(map[string]*ocr.Result)
+		}
+		entry.OCR[area] = result
+		return nil
+	})
+}
+
+// SearchText returns the screenshots whose recognized text contains query, ignoring case, sorted by name
+func (m *Manifest) SearchText(query string) []TextMatch {
+	query = strings.ToLower(query)
+	var matches []TextMatch
+	for name, entry := range m.Entries {
+		var lines []string
+		for _, area := range sortedAreas(entry.OCR) {
+			for _, line := range entry.OCR[area].Lines {
+				if strings.Contains(strings.ToLower(line.Text), query) {
+					lines = append(lines, fmt.Sprintf("[%s] %s", area, line.Text))
+				}
+			}
+		}
+		if len(lines) > 0 {
+			matches = append(matches, TextMatch{Name: name, Notes: entry.Notes, Lines: lines})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Name < matches[j].Name })
+	return matches
+}
+
+// sortedAreas returns the OCR areas of an entry, the full screenshot first
+func sortedAreas(results map[string]*ocr.Result) []string {
+	areas := make([]string, 0, len(results))
+	for area := range results {
+		areas = append(areas, area)
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		if (areas[i] == OCRFullArea) != (areas[j] == OCRFullArea) {
+			return areas[i] == OCRFullArea
+		}
+		return areas[i] < areas[j]
+	})
+	return areas
+}
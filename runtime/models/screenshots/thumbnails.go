@@ -0,0 +1,79 @@
+package screenshots
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// ThumbnailPath returns where the thumbnail of a screenshot with the given SHA-256 is cached
+func ThumbnailPath(vaultPath, sum string) string {
+	return filepath.Join(vaultPath, vault.META_DIR, vault.THUMBNAIL_DIR, sum+".jpg")
+}
+
+// LoadThumbnail returns a thumbnail of the named screenshot, at most utils.ThumbnailEdge px on its longest side.
+// Thumbnails are cached under meta/thumbnails by content hash, so renamed or re-imported screenshots reuse them
+// and edited ones get a fresh thumbnail.
+func LoadThumbnail(vaultPath, name string) (image.Image, error) {
+	fullPath, err := utils.BuildSecurePath(vaultPath, vault.SCREENSHOT_DIR, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read screenshot '%s': %w", name, err)
+	}
+
+	cachePath := ThumbnailPath(vaultPath, utils.HashBytes(data))
+	if cached, err := os.ReadFile(cachePath); err == nil {
+		if thumb, err := jpeg.Decode(bytes.NewReader(cached)); err == nil {
+			return thumb, nil
+		}
+		// A corrupt cache entry is rebuilt below
+	}
+
+	img, _, err := utils.DecodeImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot '%s': %w", name, err)
+	}
+	thumb := utils.Thumbnail(img, utils.ThumbnailEdge)
+
+	encoded, err := utils.EncodeJPEG(thumb, utils.ThumbnailQuality)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail of '%s': %w", name, err)
+	}
+	if err := writeThumbnail(cachePath, encoded); err != nil {
+		return nil, err
+	}
+	return thumb, nil
+}
+
+// writeThumbnail saves a thumbnail to the cache. A temp file keeps concurrent readers from seeing a partial write.
+func writeThumbnail(cachePath string, data []byte) error {
+	if err := utils.EnsureDirExists(filepath.Dir(cachePath), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cachePath), ".thumbnail-*")
+	if err != nil {
+		return fmt.Errorf("failed to cache thumbnail: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to cache thumbnail: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to cache thumbnail: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), cachePath); err != nil {
+		return fmt.Errorf("failed to cache thumbnail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package screenshots
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// TimelineDateLayout is the day heading of a formatted timeline
+const TimelineDateLayout = utils.DateLayout
+
+// CaptureTime returns when the screenshot was taken, or the zero time if that is unknown
+func (e *Entry) CaptureTime() time.Time {
+	return parseTime(e.CapturedAt)
+}
+
+// Timeline returns the screenshots captured within [from, to), oldest first.
+// A zero from or to leaves that end of the range open. Screenshots with no known capture time are left out.
+func (m *Manifest) Timeline(from, to time.Time) []*Entry {
+	var timeline []*Entry
+	for _, entry := range m.Entries {
+		captured := entry.CaptureTime()
+		if captured.IsZero() {
+			continue
+		}
+		if !from.IsZero() && captured.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !captured.Before(to) {
+			continue
+		}
+		timeline = append(timeline, entry)
+	}
+
+	sort.Slice(timeline, func(i, j int) bool {
+		a, b := timeline[i].CaptureTime(), timeline[j].CaptureTime()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return timeline[i].Name < timeline[j].Name
+	})
+	return timeline
+}
+
+// LoadTimeline indexes the screenshots dir and returns the screenshots captured within [from, to), oldest first
+func LoadTimeline(vaultPath string, from, to time.Time) ([]*Entry, error) {
+	var timeline []*Entry
+	err := Update(vaultPath, func(m *Manifest) error {
+		if _, err := m.Index(filepath.Join(vaultPath, vault.SCREENSHOT_DIR)); err != nil {
+			return err
+		}
+		timeline = m.Timeline(from, to)
+		return nil
+	})
+	return timeline, err
+}
+
+// FormatTimeline renders screenshots as markdown grouped by the local day they were captured on.
+// Each line has the capture time, where it was read from, the analysis status and any notes embedding the screenshot.
+func FormatTimeline(timeline []*Entry) string {
+	var sb strings.Builder
+	day := ""
+	for _, entry := range timeline {
+		captured := entry.CaptureTime().Local()
+		if d := captured.Format(TimelineDateLayout); d != day {
+			if day != "" {
+				sb.WriteString("\n")
+			}
+			day = d
+			fmt.Fprintf(&sb, "## %s (%s)\n\n", day, captured.Format("Monday"))
+		}
+
+		fmt.Fprintf(&sb, "- %s `%s` (time from %s, %s)", captured.Format("15:04:05"), entry.Name, entry.CaptureSource, entry.CurrentStatus())
+		if len(entry.Notes) > 0 {
+			fmt.Fprintf(&sb, " notes: %s", strings.Join(entry.Notes, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+package screenshots
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLoadTimeline(t *testing.T) {
+	vaultPath := t.TempDir()
+	local := func(day, hour, min int) time.Time { return time.Date(2025, 4, day, hour, min, 0, 0, time.Local) }
+
+	// A capture time from each source: EXIF (seeded, since the test file has none), the file name and the import
+	seedManifest(t, vaultPath,
+		&Entry{Name: "exif.png", Status: StatusNew, CapturedAt: local(15, 9, 30).Format(time.RFC3339), CaptureSource: CaptureSourceExif},
+		&Entry{Name: "next-day.png", Status: StatusNew, CapturedAt: local(16, 0, 0).Format(time.RFC3339), CaptureSource: CaptureSourceExif},
+	)
+	writeScreenshot(t, vaultPath, "20250415185723_1.jpg")
+	for name, captured := range map[string]time.Time{
+		"drive.png":   local(15, 23, 59),
+		"before.png":  local(14, 23, 0),
+		"undated.png": {},
+	} {
+		if _, err := Import(vaultPath, name, []byte("screenshot "+name), Origin{Source: SourceGoogleDrive, CapturedAt: captured}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := LinkNote(vaultPath, "rooms/nook.md", []string{"drive.png"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{
+			name: "open range",
+			want: []string{"before.png", "exif.png", "20250415185723_1.jpg", "drive.png", "next-day.png"},
+		},
+		{
+			name: "from",
+			from: local(15, 0, 0),
+			want: []string{"exif.png", "20250415185723_1.jpg", "drive.png", "next-day.png"},
+		},
+		{
+			name: "to is exclusive",
+			to:   local(15, 18, 57).Add(23 * time.Second),
+			want: []string{"before.png", "exif.png"},
+		},
+		{
+			// A date given as the end of a range covers that whole day, up to midnight
+			name: "whole day",
+			from: local(15, 0, 0),
+			to:   local(16, 0, 0),
+			want: []string{"exif.png", "20250415185723_1.jpg", "drive.png"},
+		},
+		{
+			name: "empty",
+			from: local(20, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeline, err := LoadTimeline(vaultPath, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("LoadTimeline() error = %v", err)
+			}
+			if got := names(timeline); !slices.Equal(got, tt.want) {
+				t.Errorf("LoadTimeline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	timeline, err := LoadTimeline(vaultPath, local(14, 0, 0), local(16, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## 2025-04-14 (Monday)\n\n" +
+		"- 23:00:00 `before.png` (time from import, new)\n" +
+		"\n" +
+		"## 2025-04-15 (Tuesday)\n\n" +
+		"- 09:30:00 `exif.png` (time from exif, new)\n" +
+		"- 18:57:23 `20250415185723_1.jpg` (time from filename, new)\n" +
+		"- 23:59:00 `drive.png` (time from import, noted) notes: rooms/nook.md\n"
+	if got := FormatTimeline(timeline); got != want {
+		t.Errorf("FormatTimeline() = %q, want %q", got, want)
+	}
+	if got := FormatTimeline(nil); got != "" {
+		t.Errorf("FormatTimeline(nil) = %q, want an empty string", got)
+	}
+}
@@ -0,0 +1,217 @@
+package spoilers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// Spoiler levels, from most to least protective
+const (
+	// LevelStrict never uses external information, only the player's notes
+	LevelStrict = "strict"
+	// LevelFiltered uses external information only about what the notes already cover, with consent
+	LevelFiltered = "filtered"
+	// LevelHints additionally allows nudges, never solutions, on puzzles the notes cover
+	LevelHints = "hints"
+	// LevelFull allows full explanations, solutions included, of anything the notes cover
+	LevelFull = "full"
+
+	// DefaultLevel is used when neither the config nor set_spoiler_level chose one
+	DefaultLevel = LevelFiltered
+)
+
+// Levels lists every spoiler level, from most to least protective
+var Levels = []string{LevelStrict, LevelFiltered, LevelHints, LevelFull}
+
+// Summaries describe each level in a sentence
+var Summaries = map[string]string{
+	LevelStrict:   "Only the player's notes. No training data, wikis or web searches, even about documented content.",
+	LevelFiltered: "External information only about content the player has documented, with a spoiler warning and consent.",
+	LevelHints:    "As filtered, plus gentle hints (never solutions) on documented puzzles when the player asks for help.",
+	LevelFull:     "Full explanations of documented mechanics, puzzles and story, solutions included when asked. Undocumented content stays off limits.",
+}
+
+// ErrInvalidSettings is returned for saved settings with an unknown level or category, e.g. after a hand edit
+var ErrInvalidSettings = errors.New("invalid spoiler settings")
+
+// settingsMu serializes read-modify-write cycles of the spoiler settings within this process
+var settingsMu sync.Mutex
+
+// Policy is a spoiler level with optional per-category overrides
+type Policy struct {
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// Categories maps a note category to the level used for it instead of Level
+	Categories map[string]string `json:"categories,omitempty" yaml:"categories,omitempty"`
+}
+
+// CategoryLevel is a per-category override
+type CategoryLevel struct {
+	Category string
+	Level    string
+}
+
+// Validate checks that every level and category is known
+func (p Policy) Validate() error {
+	if p.Level != "" && !IsLevel(p.Level) {
+		return fmt.Errorf("invalid spoiler level '%s'. Must be one of: %v", p.Level, Levels)
+	}
+	for category, level := range p.Categories {
+		if !slices.Contains(notes.Categories, category) {
+			return fmt.Errorf("invalid category '%s'. Must be one of: %v", category, notes.Categories)
+		}
+		if !IsLevel(level) {
+			return fmt.Errorf("invalid spoiler level '%s' for category '%s'. Must be one of: %v", level, category, Levels)
+		}
+	}
+	return nil
+}
+
+// DefaultLevel returns the policy's level, or DefaultLevel if none is set
+func (p Policy) DefaultLevel() string {
+	if p.Level == "" {
+		return DefaultLevel
+	}
+	return p.Level
+}
+
+// LevelFor returns the level that applies to notes of the given category
+func (p Policy) LevelFor(category string) string {
+	if level, ok := p.Categories[category]; ok {
+		return level
+	}
+	return p.DefaultLevel()
+}
+
+// Overrides returns the per-category levels that differ from the default level, sorted by category
+func (p Policy) Overrides() []CategoryLevel {
+	var overrides []CategoryLevel
+	for category, level := range p.Categories {
+		if level != p.DefaultLevel() {
+			overrides = append(overrides, CategoryLevel{Category: category, Level: level})
+		}
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].Category < overrides[j].Category })
+	return overrides
+}
+
+// Merge returns p with the level and category overrides set in other applied on top
+func (p Policy) Merge(other Policy) Policy {
+	merged := Policy{Level: p.Level, Categories: make(map[string]string)}
+	if other.Level != "" {
+		merged.Level = other.Level
+	}
+	for _, categories := range []map[string]string{p.Categories, other.Categories} {
+		for category, level := range categories {
+			merged.Categories[category] = level
+		}
+	}
+	return merged
+}
+
+// IsLevel reports whether level is a known spoiler level
+func IsLevel(level string) bool {
+	return slices.Contains(Levels, level)
+}
+
+// SettingsPath returns the location of the spoiler settings within the vault
+func SettingsPath(vaultPath string) string {
+	return filepath.Join(vaultPath, vault.META_DIR, vault.SPOILER_SETTINGS)
+}
+
+// LoadSettings reads the levels chosen with set_spoiler_level from the vault's meta dir.
+// Missing settings are not an error; an empty policy is returned instead.
+// Settings that don't validate are rejected with ErrInvalidSettings rather than loosening the spoiler rules.
+func LoadSettings(vaultPath string) (Policy, error) {
+	var p Policy
+	path := SettingsPath(vaultPath)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to read spoiler settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("failed to parse spoiler settings: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return Policy{}, fmt.Errorf("%w in %s: %v. Fix the file or choose a level again with set_spoiler_level", ErrInvalidSettings, path, err)
+	}
+	return p, nil
+}
+
+// Effective returns the policy in force: the configured policy with the levels saved in the vault applied on top.
+// If the saved levels can't be loaded, the error is returned along with the configured policy.
+func Effective(vaultPath string, configured Policy) (Policy, error) {
+	saved, err := LoadSettings(vaultPath)
+	if err != nil {
+		return configured, err
+	}
+	return configured.Merge(saved), nil
+}
+
+// SetLevel saves level as the default level, or as the level of category if one is given.
+// An empty level removes the saved setting so the configured one applies again.
+func SetLevel(vaultPath, category, level string) error {
+	if level != "" && !IsLevel(level) {
+		return fmt.Errorf("invalid spoiler level '%s'. Must be one of: %v", level, Levels)
+	}
+	if category != "" && !slices.Contains(notes.Categories, category) {
+		return fmt.Errorf("invalid category '%s'. Must be one of: %v", category, notes.Categories)
+	}
+
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+
+	// Invalid settings are replaced, so set_spoiler_level is always a way out of a broken file
+	p, err := LoadSettings(vaultPath)
+	if errors.Is(err, ErrInvalidSettings) {
+		p = Policy{}
+	} else if err != nil {
+		return err
+	}
+	switch {
+	case category == "":
+		p.Level = level
+	case level == "":
+		delete(p.Categories, category)
+	default:
+		if p.Categories == nil {
+			p.Categories = make(map[string]string)
+		}
+		p.Categories[category] = level
+	}
+	return saveSettings(vaultPath, p)
+}
+
+func saveSettings(vaultPath string, p Policy) error {
+	path := SettingsPath(vaultPath)
+	if err := utils.EnsureDirExists(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal spoiler settings: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves truncated settings behind
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write spoiler settings: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace spoiler settings: %w", err)
+	}
+	return nil
+}
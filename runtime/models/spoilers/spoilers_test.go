@@ -0,0 +1,36 @@
+package spoilers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSettings_Invalid(t *testing.T) {
+	vaultPath := t.TempDir()
+	path := SettingsPath(vaultPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"level": "spoil-everything"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadSettings(vaultPath); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("LoadSettings() error = %v, want ErrInvalidSettings", err)
+	}
+	configured := Policy{Level: LevelStrict}
+	if p, err := Effective(vaultPath, configured); err == nil || p.DefaultLevel() != LevelStrict {
+		t.Errorf("Effective() = %v, %v, want the configured policy and an error", p, err)
+	}
+
+	// Choosing a level again replaces the broken settings
+	if err := SetLevel(vaultPath, "", LevelHints); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	p, err := Effective(vaultPath, configured)
+	if err != nil || p.DefaultLevel() != LevelHints {
+		t.Errorf("Effective() = %v, %v, want level %s", p, err, LevelHints)
+	}
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+const (
+	// BACKUP_DIR is the remote folder, relative to the store's root, that holds vault snapshots
+	BACKUP_DIR = "vault_backups"
+	// BACKUP_OBJECTS_DIR holds file contents named by their SHA-256, shared by all snapshots
+	BACKUP_OBJECTS_DIR = "objects"
+	// BACKUP_SNAPSHOTS_DIR holds one JSON manifest per snapshot
+	BACKUP_SNAPSHOTS_DIR = "snapshots"
+
+	// snapshotIDFormat sorts lexicographically in time order
+	snapshotIDFormat = "20060102T150405.000Z"
+)
+
+// derivedDirs are vault subdirs, slash separated, whose contents are rebuilt on demand and so never backed up
+var derivedDirs = []string{path.Join(vault.META_DIR, vault.THUMBNAIL_DIR)}
+
+// Snapshot is a point-in-time listing of backed up vault files
+type Snapshot struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+	// Files maps a slash separated path relative to the vault root to the SHA-256 of its content
+	Files map[string]string `json:"files"`
+}
+
+// BackupResult summarizes a BackupVault call
+type BackupResult struct {
+	Snapshot *Snapshot `json:"snapshot"`
+	Uploaded int       `json:"uploaded"`
+	Reused   int       `json:"reused"`
+}
+
+// NewSnapshot hashes every non-hidden file under the given vault subdirs.
+// Subdirs that don't exist are skipped, as are caches such as screenshot thumbnails.
+// Returns the snapshot and the content of each file keyed by hash, ready for upload.
+func NewSnapshot(vaultPath string, dirs []string) (*Snapshot, map[string][]byte, error) {
+	now := time.Now().UTC()
+	snapshot := &Snapshot{
+		ID:        now.Format(snapshotIDFormat),
+		CreatedAt: now.Format(time.RFC3339),
+		Files:     make(map[string]string),
+	}
+	objects := make(map[string][]byte)
+
+	for _, dir := range dirs {
+		root := filepath.Join(vaultPath, dir)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+
+		files, err := utils.ListFiles(root)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, rel := range files {
+			vaultRel := filepath.ToSlash(filepath.Join(dir, rel))
+			if isDerived(vaultRel) {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(root, rel))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read '%s': %w", rel, err)
+			}
+			sum := utils.HashBytes(data)
+			snapshot.Files[vaultRel] = sum
+			objects[sum] = data
+		}
+	}
+	return snapshot, objects, nil
+}
+
+// isDerived reports whether the slash separated vault path is inside one of the derivedDirs
+func isDerived(vaultRel string) bool {
+	for _, dir := range derivedDirs {
+		if strings.HasPrefix(vaultRel, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// FileName is the name of the snapshot's manifest within BACKUP_SNAPSHOTS_DIR
+func (s *Snapshot) FileName() string {
+	return s.ID + ".json"
+}
+
+// Marshal encodes the snapshot manifest
+func (s *Snapshot) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// ParseSnapshot decodes a snapshot manifest
+func ParseSnapshot(data []byte) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if s.Files == nil {
+		s.Files = make(map[string]string)
+	}
+	return &s, nil
+}
+
+// RestoreFile writes a single snapshot file back into the vault, verifying its content hash
+func RestoreFile(vaultPath, relPath, sum string, data []byte) error {
+	if got := utils.HashBytes(data); got != sum {
+		return fmt.Errorf("content of '%s' does not match snapshot hash", relPath)
+	}
+
+	cleanPath, err := utils.ValidatePath(filepath.FromSlash(relPath))
+	if err != nil {
+		return err
+	}
+	fullPath, err := utils.BuildSecurePath(vaultPath, "", cleanPath)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.EnsureDirExists(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to restore '%s': %w", relPath, err)
+	}
+	return nil
+}
@@ -1,7 +1,34 @@
 package storage
 
+import "time"
+
 type Store interface {
 	GetFiles(filename string) ([]string, error)
 	ListFiles() ([]string, error)
 	MoveFile(filename, destination string) error
+
+	// BackupVault uploads the given vault subdirs as a new snapshot under BACKUP_DIR.
+	// File contents are stored by hash, so only files that changed since any earlier snapshot are uploaded.
+	BackupVault(vaultPath string, dirs []string) (*BackupResult, error)
+	// RestoreVault rebuilds the vault at vaultPath from the newest snapshot under BACKUP_DIR
+	RestoreVault(vaultPath string) (*Snapshot, error)
+
+	// ListTree lists every file below the remote dir (relative to the store's root), keyed by slash separated path.
+	// A dir that doesn't exist yet is an empty tree.
+	ListTree(dir string) (map[string]RemoteFile, error)
+	// ReadFile downloads the file at path inside the remote dir
+	ReadFile(dir, path string) ([]byte, error)
+	// WriteFile creates or overwrites the file at path inside the remote dir, creating parent folders as needed
+	WriteFile(dir, path string, data []byte) error
+	// DeleteFile removes the file at path inside the remote dir
+	DeleteFile(dir, path string) error
+}
+
+// RemoteFile describes a file in a remote tree
+type RemoteFile struct {
+	// Path is slash separated and relative to the tree's root
+	Path string `json:"path"`
+	// MD5 is the hex MD5 checksum of the content, which every backend can report without downloading it
+	MD5          string    `json:"md5"`
+	ModifiedTime time.Time `json:"modified_time"`
 }
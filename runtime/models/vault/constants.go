@@ -4,4 +4,15 @@ const (
 	META_DIR       = "meta"
 	SCREENSHOT_DIR = "screenshots"
 	NOTES_DIR      = "notes"
+
+	// SCREENSHOT_MANIFEST is the file within META_DIR that tracks every imported screenshot
+	SCREENSHOT_MANIFEST = "screenshots.json"
+	// SYNC_STATE is the file within META_DIR that records what each note looked like at the last sync
+	SYNC_STATE = "sync_state.json"
+	// SPOILER_SETTINGS is the file within META_DIR that records the spoiler levels chosen with set_spoiler_level
+	SPOILER_SETTINGS = "spoilers.json"
+	// GUARD_AUDIT_LOG is the JSONL file within META_DIR that records every decision of the note content guard
+	GUARD_AUDIT_LOG = "guard_audit.jsonl"
+	// THUMBNAIL_DIR is the dir within META_DIR that caches screenshot thumbnails, named by the SHA-256 of the screenshot
+	THUMBNAIL_DIR = "thumbnails"
 )
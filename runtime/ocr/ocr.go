@@ -0,0 +1,55 @@
+// Package ocr recognizes in-game text (letters, blackboards, signs) in screenshots with an offline OCR engine
+package ocr
+
+import (
+	"context"
+	"image"
+	"strings"
+)
+
+// Box is a bounding box in pixels of the original screenshot
+type Box struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Line is a line of recognized text
+type Line struct {
+	Text string `json:"text"`
+	// Confidence is the engine's mean word confidence for the line, 0-100
+	Confidence float64 `json:"confidence"`
+	Box        Box     `json:"box"`
+}
+
+// Result is everything recognized in one area of a screenshot
+type Result struct {
+	Engine       string `json:"engine"`
+	RecognizedAt string `json:"recognized_at"`
+	// Area is the part of the screenshot that was read
+	Area  Box    `json:"area"`
+	Lines []Line `json:"lines"`
+}
+
+// Engine recognizes text in an image
+type Engine interface {
+	// Name identifies the engine in stored results
+	Name() string
+	// Recognize returns the lines of text in img. Boxes are relative to img.Bounds().Min.
+	Recognize(ctx context.Context, img image.Image) ([]Line, error)
+}
+
+// Text joins the recognized lines, one per line
+func (r *Result) Text() string {
+	lines := make([]string, len(r.Lines))
+	for i, line := range r.Lines {
+		lines[i] = line.Text
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BoxFromRect converts an image rectangle to a Box
+func BoxFromRect(rect image.Rectangle) Box {
+	return Box{X: rect.Min.X, Y: rect.Min.Y, Width: rect.Dx(), Height: rect.Dy()}
+}
@@ -0,0 +1,170 @@
+package ocr
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// DefaultLanguage is the tesseract language used when none is configured
+	DefaultLanguage = "eng"
+
+	// minTextEdge is the longest side below which images are upscaled before OCR.
+	// Tesseract reads best when text is around 30px tall, and crops of in-game text are often smaller.
+	minTextEdge = 1200
+	// tsvLevelWord is the TSV level of single words
+	tsvLevelWord = 5
+)
+
+// Tesseract runs the tesseract command line tool
+type Tesseract struct {
+	Path     string
+	Language string
+}
+
+// NewTesseract finds the tesseract binary, at path if given or else on the PATH
+func NewTesseract(path, language string) (*Tesseract, error) {
+	if path == "" {
+		path = "tesseract"
+	}
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("tesseract not found: %w", err)
+	}
+	if language == "" {
+		language = DefaultLanguage
+	}
+	return &Tesseract{Path: resolved, Language: language}, nil
+}
+
+func (t *Tesseract) Name() string {
+	return "tesseract"
+}
+
+// Recognize pipes img to tesseract as a PNG and parses its TSV output
+func (t *Tesseract) Recognize(ctx context.Context, img image.Image) ([]Line, error) {
+	scale := 1
+	if bounds := img.Bounds(); max(bounds.Dx(), bounds.Dy()) < minTextEdge {
+		scale = max(1, minTextEdge/max(bounds.Dx(), bounds.Dy()))
+		scale = min(scale, 4)
+	}
+	input := img
+	if scale > 1 {
+		bounds := img.Bounds()
+		scaled := image.NewRGBA(image.Rect(0, 0, bounds.Dx()*scale, bounds.Dy()*scale))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+		input = scaled
+	}
+
+	var stdin bytes.Buffer
+	if err := png.Encode(&stdin, input); err != nil {
+		return nil, fmt.Errorf("failed to encode image for tesseract: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.Path, "stdin", "stdout", "-l", t.Language, "tsv")
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	lines, err := parseTSV(&stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map boxes back onto the image that was passed in
+	origin := img.Bounds().Min
+	for i := range lines {
+		box := &lines[i].Box
+		box.X, box.Y = box.X/scale+origin.X, box.Y/scale+origin.Y
+		box.Width, box.Height = box.Width/scale, box.Height/scale
+	}
+	return lines, nil
+}
+
+// lineKey identifies a line in tesseract's page layout
+type lineKey struct {
+	page, block, paragraph, line int
+}
+
+// parseTSV groups the words of tesseract's TSV output into lines, in reading order.
+// Columns: level page_num block_num par_num line_num word_num left top width height conf text
+func parseTSV(r io.Reader) ([]Line, error) {
+	type pending struct {
+		words      []string
+		confidence float64
+		rect       image.Rectangle
+	}
+	var order []lineKey
+	byKey := make(map[lineKey]*pending)
+
+	scanner := bufio.NewScanner(r)
+	header := true
+	for scanner.Scan() {
+		if header {
+			header = false
+			continue
+		}
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 12 {
+			continue
+		}
+
+		nums := make([]int, 10)
+		for i := range nums {
+			n, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("malformed tesseract output %q: %w", scanner.Text(), err)
+			}
+			nums[i] = n
+		}
+		confidence, err := strconv.ParseFloat(fields[10], 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed tesseract confidence %q: %w", fields[10], err)
+		}
+		text := strings.TrimSpace(strings.Join(fields[11:], "\t"))
+		// Only words carry text; a negative confidence marks layout rows
+		if nums[0] != tsvLevelWord || text == "" || confidence < 0 {
+			continue
+		}
+
+		key := lineKey{nums[1], nums[2], nums[3], nums[4]}
+		rect := image.Rect(nums[6], nums[7], nums[6]+nums[8], nums[7]+nums[9])
+		line, ok := byKey[key]
+		if !ok {
+			line = &pending{rect: rect}
+			byKey[key] = line
+			order = append(order, key)
+		}
+		line.words = append(line.words, text)
+		line.confidence += confidence
+		line.rect = line.rect.Union(rect)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tesseract output: %w", err)
+	}
+
+	lines := make([]Line, len(order))
+	for i, key := range order {
+		p := byKey[key]
+		lines[i] = Line{
+			Text:       strings.Join(p.words, " "),
+			Confidence: p.confidence / float64(len(p.words)),
+			Box:        BoxFromRect(p.rect),
+		}
+	}
+	return lines, nil
+}
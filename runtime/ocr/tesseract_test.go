@@ -0,0 +1,45 @@
+package ocr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTSV(t *testing.T) {
+	tsv := strings.Join([]string{
+		"level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext",
+		"1\t1\t0\t0\t0\t0\t0\t0\t800\t600\t-1\t",
+		"2\t1\t1\t0\t0\t0\t10\t20\t300\t80\t-1\t",
+		"4\t1\t1\t1\t1\t0\t10\t20\t300\t30\t-1\t",
+		"5\t1\t1\t1\t1\t1\t10\t20\t100\t30\t96.5\tDear",
+		"5\t1\t1\t1\t1\t2\t120\t22\t190\t28\t90.5\tSimon,",
+		"4\t1\t1\t1\t2\t0\t10\t70\t200\t30\t-1\t",
+		"5\t1\t1\t1\t2\t1\t10\t70\t200\t30\t50\tMt.Holly",
+		"5\t1\t1\t1\t2\t2\t215\t70\t5\t30\t95\t ",
+	}, "\n")
+
+	lines, err := parseTSV(strings.NewReader(tsv))
+	if err != nil {
+		t.Fatalf("parseTSV() error = %v", err)
+	}
+	want := []Line{
+		{Text: "Dear Simon,", Confidence: 93.5, Box: Box{X: 10, Y: 20, Width: 300, Height: 30}},
+		{Text: "Mt.Holly", Confidence: 50, Box: Box{X: 10, Y: 70, Width: 200, Height: 30}},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("parseTSV() = %+v, want %+v", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestParseTSV_Malformed(t *testing.T) {
+	tsv := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+		"5\t1\t1\t1\tx\t1\t10\t20\t100\t30\t96\tDear"
+	if _, err := parseTSV(strings.NewReader(tsv)); err == nil {
+		t.Error("parseTSV() expected an error for a non-numeric column")
+	}
+}
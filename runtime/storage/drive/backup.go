@@ -0,0 +1,156 @@
+package drive
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// BackupVault uploads the given vault subdirs to vault_backups/ under the configured Google Drive folder.
+// Contents are stored once per hash in vault_backups/objects and each call writes a new manifest to vault_backups/snapshots.
+func (g *GoogleDrive) BackupVault(vaultPath string, dirs []string) (*storage.BackupResult, error) {
+	snapshot, objects, err := storage.NewSnapshot(vaultPath, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	objectsID, snapshotsID, err := g.backupFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := g.listChildren(objectsID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup objects: %w", err)
+	}
+
+	result := &storage.BackupResult{Snapshot: snapshot}
+	for sum, data := range objects {
+		if _, ok := existing[sum]; ok {
+			result.Reused++
+			continue
+		}
+		if err := g.upload(objectsID, sum, data); err != nil {
+			return nil, fmt.Errorf("failed to upload backup object: %w", err)
+		}
+		result.Uploaded++
+	}
+
+	// Write the manifest last so a partial upload never produces a snapshot that references missing objects
+	manifest, err := snapshot.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err := g.upload(snapshotsID, snapshot.FileName(), manifest); err != nil {
+		return nil, fmt.Errorf("failed to upload snapshot manifest: %w", err)
+	}
+
+	return result, nil
+}
+
+// RestoreVault downloads the newest snapshot from vault_backups/ into vaultPath.
+// Files in the snapshot overwrite local copies; local files that aren't in the snapshot are left untouched.
+func (g *GoogleDrive) RestoreVault(vaultPath string) (*storage.Snapshot, error) {
+	objectsID, snapshotsID, err := g.backupFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := g.listChildren(snapshotsID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots found in '%s'", storage.BACKUP_DIR)
+	}
+
+	// Snapshot names sort in time order, so the newest is last
+	names := make([]string, 0, len(snapshots))
+	for name := range snapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	newest := names[len(names)-1]
+
+	data, err := g.download(snapshots[newest])
+	if err != nil {
+		return nil, fmt.Errorf("failed to download snapshot '%s': %w", newest, err)
+	}
+	snapshot, err := storage.ParseSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := g.listChildren(objectsID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup objects: %w", err)
+	}
+
+	for relPath, sum := range snapshot.Files {
+		objectID, ok := objects[sum]
+		if !ok {
+			return nil, fmt.Errorf("snapshot '%s' references missing object for '%s'", newest, relPath)
+		}
+		content, err := g.download(objectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download '%s': %w", relPath, err)
+		}
+		if err := storage.RestoreFile(vaultPath, relPath, sum, content); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// backupFolders finds or creates vault_backups/objects and vault_backups/snapshots, returning their IDs
+func (g *GoogleDrive) backupFolders() (string, string, error) {
+	backupID, err := g.findOrCreateSubfolder(storage.BACKUP_DIR)
+	if err != nil {
+		return "", "", err
+	}
+	objectsID, err := g.findOrCreateFolderIn(backupID, storage.BACKUP_OBJECTS_DIR)
+	if err != nil {
+		return "", "", err
+	}
+	snapshotsID, err := g.findOrCreateFolderIn(backupID, storage.BACKUP_SNAPSHOTS_DIR)
+	if err != nil {
+		return "", "", err
+	}
+	return objectsID, snapshotsID, nil
+}
+
+// listChildren maps the names of all untrashed files directly inside parentID to their IDs, following pagination
+func (g *GoogleDrive) listChildren(parentID string) (map[string]string, error) {
+	query := NewQuery().InParents(parentID).Trashed(false).NotMimeType(FOLDER_MIME_TYPE)
+
+	children := make(map[string]string)
+	call := g.Client.Files.List().Q(query.String()).PageSize(max_page_size).Fields("nextPageToken", "files(id, name)")
+	for {
+		result, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range result.Files {
+			children[file.Name] = file.Id
+		}
+		if result.NextPageToken == "" {
+			return children, nil
+		}
+		call.PageToken(result.NextPageToken)
+	}
+}
+
+// upload creates a new file called name inside parentID with the given content
+func (g *GoogleDrive) upload(parentID, name string, data []byte) error {
+	file := &drive.File{
+		Name:    name,
+		Parents: []string{parentID},
+	}
+	_, err := g.Client.Files.Create(file).Media(bytes.NewReader(data)).Do()
+	return err
+}
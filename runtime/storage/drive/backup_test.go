@@ -0,0 +1,116 @@
+package drive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+)
+
+func writeVaultFile(t *testing.T, vaultPath, relPath, content string) {
+	t.Helper()
+
+	fullPath := filepath.Join(vaultPath, relPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Fatalf("Failed to create dir for %s: %v", relPath, err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", relPath, err)
+	}
+}
+
+func TestGoogleDrive_BackupVault(t *testing.T) {
+	gd, fake, folderID := newTestStore(t)
+	dirs := []string{vault.NOTES_DIR, vault.META_DIR}
+
+	writeVaultFile(t, gd.VaultPath, "notes/rooms/parlor.md", "# Parlor")
+	writeVaultFile(t, gd.VaultPath, "notes/people/simon.md", "# Simon")
+	writeVaultFile(t, gd.VaultPath, "notes/.obsidian/workspace.json", "{}")
+	writeVaultFile(t, gd.VaultPath, "meta/screenshots.json", "{}")
+	writeVaultFile(t, gd.VaultPath, "screenshots/parlor.png", "not backed up")
+	writeVaultFile(t, gd.VaultPath, "meta/thumbnails/abc123.jpg", "cache, not backed up")
+
+	result, err := gd.BackupVault(gd.VaultPath, dirs)
+	if err != nil {
+		t.Fatalf("BackupVault() failed: %v", err)
+	}
+	if len(result.Snapshot.Files) != 3 {
+		t.Errorf("Snapshot should contain 3 files, got: %v", result.Snapshot.Files)
+	}
+	if result.Uploaded != 3 || result.Reused != 0 {
+		t.Errorf("First backup should upload everything, got uploaded=%d reused=%d", result.Uploaded, result.Reused)
+	}
+	if _, ok := result.Snapshot.Files["notes/rooms/parlor.md"]; !ok {
+		t.Errorf("Snapshot paths should be relative to the vault root, got: %v", result.Snapshot.Files)
+	}
+
+	backups, ok := fake.FindChild(folderID, storage.BACKUP_DIR)
+	if !ok {
+		t.Fatalf("Backup folder %s should be created", storage.BACKUP_DIR)
+	}
+	snapshotsDir, _ := fake.FindChild(backups.ID, storage.BACKUP_SNAPSHOTS_DIR)
+	if snapshots := fake.Children(snapshotsDir.ID); len(snapshots) != 1 {
+		t.Errorf("Expected 1 snapshot manifest, got %d", len(snapshots))
+	}
+
+	// Only the changed file is uploaded by the next backup
+	writeVaultFile(t, gd.VaultPath, "notes/rooms/parlor.md", "# Parlor\n\nThree boxes")
+	result, err = gd.BackupVault(gd.VaultPath, dirs)
+	if err != nil {
+		t.Fatalf("Second BackupVault() failed: %v", err)
+	}
+	if result.Uploaded != 1 || result.Reused != 2 {
+		t.Errorf("Second backup should be incremental, got uploaded=%d reused=%d", result.Uploaded, result.Reused)
+	}
+	if snapshots := fake.Children(snapshotsDir.ID); len(snapshots) != 2 {
+		t.Errorf("Expected 2 snapshot manifests, got %d", len(snapshots))
+	}
+}
+
+func TestGoogleDrive_RestoreVault(t *testing.T) {
+	gd, _, _ := newTestStore(t)
+	dirs := []string{vault.NOTES_DIR, vault.META_DIR}
+
+	writeVaultFile(t, gd.VaultPath, "notes/rooms/parlor.md", "# Parlor")
+	if _, err := gd.BackupVault(gd.VaultPath, dirs); err != nil {
+		t.Fatalf("BackupVault() failed: %v", err)
+	}
+	writeVaultFile(t, gd.VaultPath, "notes/rooms/parlor.md", "# Parlor v2")
+	writeVaultFile(t, gd.VaultPath, "meta/screenshots.json", `{"entries":{}}`)
+	if _, err := gd.BackupVault(gd.VaultPath, dirs); err != nil {
+		t.Fatalf("BackupVault() failed: %v", err)
+	}
+
+	// Rebuild into an empty vault
+	restored := t.TempDir()
+	snapshot, err := gd.RestoreVault(restored)
+	if err != nil {
+		t.Fatalf("RestoreVault() failed: %v", err)
+	}
+	if len(snapshot.Files) != 2 {
+		t.Errorf("Newest snapshot should have 2 files, got: %v", snapshot.Files)
+	}
+
+	for relPath, expected := range map[string]string{
+		"notes/rooms/parlor.md": "# Parlor v2",
+		"meta/screenshots.json": `{"entries":{}}`,
+	} {
+		data, err := os.ReadFile(filepath.Join(restored, relPath))
+		if err != nil {
+			t.Fatalf("Expected %s to be restored: %v", relPath, err)
+		}
+		if string(data) != expected {
+			t.Errorf("Restored %s = %q, expected %q", relPath, data, expected)
+		}
+	}
+}
+
+func TestGoogleDrive_RestoreVault_NoSnapshots(t *testing.T) {
+	gd, _, _ := newTestStore(t)
+
+	if _, err := gd.RestoreVault(t.TempDir()); err == nil {
+		t.Error("RestoreVault() should fail when there are no snapshots")
+	}
+}
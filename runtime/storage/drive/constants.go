@@ -1,10 +1,11 @@
 package drive
 
 const (
-	CONFIG_DIR     = ".blueprince_mcp"
-	CONFIG_FILE    = "drive_config.json"
-	TOKEN_FILE     = "drive_token.json"
-	APP_CREDS_FILE = ".credentials.json"
-	max_page_size  = 500
-	archive_dir    = "downloaded_screenshots"
+	CONFIG_DIR       = ".blueprince_mcp"
+	CONFIG_FILE      = "drive_config.json"
+	TOKEN_FILE       = "drive_token.json"
+	APP_CREDS_FILE   = ".credentials.json"
+	FOLDER_MIME_TYPE = "application/vnd.google-apps.folder"
+	max_page_size    = 500
+	archive_dir      = "downloaded_screenshots"
 )
@@ -3,22 +3,25 @@ package drive
 import (
 	"fmt"
 	"io"
-	"os"
+	"time"
 
-	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
 	"github.com/myungbeans/blueprince-mcp/runtime/utils"
 	"google.golang.org/api/drive/v3"
 )
 
-// GetFile downloads a file from Google Drive to local storage
+// GetFiles downloads files from Google Drive to local storage.
+// Files whose content is already in the vault (by SHA-256) are not written again, but are still archived in Drive.
+// Returns the names of the newly imported files.
 func (g *GoogleDrive) GetFiles(filename string) ([]string, error) {
-	query := fmt.Sprintf("'%s' in parents and trashed=false", g.FolderID)
+	query := NewQuery()
 	if filename != "" {
 		// Find the file in Google Drive folder
-		query = fmt.Sprintf("name='%s' and %s", filename, query)
+		query.Name(filename)
 	}
+	query.InParents(g.FolderID).Trashed(false).NotMimeType(FOLDER_MIME_TYPE)
 
-	result, err := g.Client.Files.List().Q(query).PageSize(max_page_size).Do()
+	result, err := g.Client.Files.List().Q(query.String()).PageSize(max_page_size).Fields("files(id, name, createdTime)").Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to search for file '%s': %w", filename, err)
 	}
@@ -32,50 +35,47 @@ func (g *GoogleDrive) GetFiles(filename string) ([]string, error) {
 		return nil, err
 	}
 
-	files := make([]string, len(result.Files))
-	for i, file := range result.Files {
-		// Download file content
-		response, err := g.Client.Files.Get(file.Id).Download()
+	files := []string{}
+	for _, file := range result.Files {
+		data, err := g.download(file.Id)
 		if err != nil {
-			return nil, fmt.Errorf("failed to download file '%s': %w", filename, err)
+			return nil, fmt.Errorf("failed to download file '%s': %w", file.Name, err)
 		}
-		defer response.Body.Close()
 
-		// Create local file
-		// This needs to be the vaultpath + screenshotsdir +
-		fullPath, err := utils.BuildSecurePath(g.VaultPath, vault.SCREENSHOT_DIR, filename)
-		if err != nil {
-			return nil, fmt.Errorf("Security validation failed for img path %q: %w", filename, err)
-		}
+		// Drive only knows when the file was uploaded, which is the closest it gets to the capture time
+		origin := screenshots.Origin{Source: screenshots.SourceGoogleDrive}
+		origin.CapturedAt, _ = time.Parse(time.RFC3339, file.CreatedTime)
 
-		newImgFile, err := os.Create(fullPath)
+		saved, err := screenshots.Import(g.VaultPath, file.Name, data, origin)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create local file '%s': %w", fullPath, err)
+			return nil, err
 		}
-		defer newImgFile.Close()
-
-		// Copy content
-		_, err = io.Copy(newImgFile, response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to copy file content: %w", err)
+		if saved != "" {
+			files = append(files, saved)
 		}
 
-		files[i] = filename
-
 		g.MoveFile(file.Id, archive_dir)
 	}
 
 	return files, nil
 }
 
+// download reads the full content of a Drive file into memory
+func (g *GoogleDrive) download(fileId string) ([]byte, error) {
+	response, err := g.Client.Files.Get(fileId).Download()
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return io.ReadAll(response.Body)
+}
+
 // ListFiles lists files in the Google Drive folder
 func (g *GoogleDrive) ListFiles() ([]string, error) {
-	// Build query to list files in the configured folder
-	query := fmt.Sprintf("'%s' in parents and trashed=false", g.FolderID)
-
-	// Add filter to exclude folders from results (only return files)
-	query += " and mimeType != 'application/vnd.google-apps.folder'"
-	result, err := g.Client.Files.List().Q(query).PageSize(max_page_size).Do()
+	// Build query to list files in the configured folder, excluding folders from results (only return files)
+	query := NewQuery().InParents(g.FolderID).Trashed(false).NotMimeType(FOLDER_MIME_TYPE)
+	result, err := g.Client.Files.List().Q(query.String()).PageSize(max_page_size).Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list files in Google Drive: %w", err)
 	}
@@ -114,9 +114,14 @@ func (g *GoogleDrive) MoveFile(fileId, destination string) error {
 
 // findOrCreateSubfolder finds or creates a subfolder within the configured Google Drive folder
 func (g *GoogleDrive) findOrCreateSubfolder(folderName string) (string, error) {
+	return g.findOrCreateFolderIn(g.FolderID, folderName)
+}
+
+// findOrCreateFolderIn finds or creates a folder directly inside the folder with ID parentID
+func (g *GoogleDrive) findOrCreateFolderIn(parentID, folderName string) (string, error) {
 	// Search for existing subfolder
-	query := fmt.Sprintf("name='%s' and mimeType='application/vnd.google-apps.folder' and '%s' in parents and trashed=false", folderName, g.FolderID)
-	result, err := g.Client.Files.List().Q(query).Do()
+	query := NewQuery().Name(folderName).MimeType(FOLDER_MIME_TYPE).InParents(parentID).Trashed(false)
+	result, err := g.Client.Files.List().Q(query.String()).Do()
 	if err != nil {
 		return "", fmt.Errorf("failed to search for subfolder '%s': %w", folderName, err)
 	}
@@ -129,8 +134,8 @@ func (g *GoogleDrive) findOrCreateSubfolder(folderName string) (string, error) {
 	// Create new subfolder
 	folder := &drive.File{
 		Name:     folderName,
-		MimeType: "application/vnd.google-apps.folder",
-		Parents:  []string{g.FolderID},
+		MimeType: FOLDER_MIME_TYPE,
+		Parents:  []string{parentID},
 	}
 
 	createdFolder, err := g.Client.Files.Create(folder).Do()
@@ -144,8 +149,8 @@ func (g *GoogleDrive) findOrCreateSubfolder(folderName string) (string, error) {
 // FindOrCreateFolder finds an existing Google Drive folder or creates a new one
 func (g *GoogleDrive) FindOrCreateFolder(folderName string) (string, error) {
 	// Search for existing folder
-	query := fmt.Sprintf("name='%s' and mimeType='application/vnd.google-apps.folder' and trashed=false", folderName)
-	r, err := g.Client.Files.List().Q(query).Do()
+	query := NewQuery().Name(folderName).MimeType(FOLDER_MIME_TYPE).Trashed(false)
+	r, err := g.Client.Files.List().Q(query.String()).Do()
 	if err != nil {
 		return "", fmt.Errorf("unable to search for folder: %w", err)
 	}
@@ -158,7 +163,7 @@ func (g *GoogleDrive) FindOrCreateFolder(folderName string) (string, error) {
 	// Create new folder
 	folder := &drive.File{
 		Name:     folderName,
-		MimeType: "application/vnd.google-apps.folder",
+		MimeType: FOLDER_MIME_TYPE,
 	}
 
 	file, err := g.Client.Files.Create(folder).Do()
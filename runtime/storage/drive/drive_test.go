@@ -1,112 +1,269 @@
 package drive
 
 import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/storage/drive/drivetest"
+
 	"google.golang.org/api/drive/v3"
 )
 
-// mockDriveService is a mock implementation for testing
-type mockDriveService struct {
-	files        map[string]*drive.File
-	listResponse *drive.FileList
-	listError    error
-	createError  error
-	nextFileID   int
-}
+// Compile-time check that GoogleDrive implements the Store interface
+var _ storage.Store = (*GoogleDrive)(nil)
+
+// newTestStore wires a GoogleDrive store to a fake Drive server and a temp vault.
+// Returns the store, the fake server and the ID of the configured screenshot folder.
+func newTestStore(t *testing.T) (*GoogleDrive, *drivetest.Server, string) {
+	t.Helper()
+
+	fake := drivetest.NewServer()
+	t.Cleanup(fake.Close)
 
-func newMockDriveService() *mockDriveService {
-	return &mockDriveService{
-		files:      make(map[string]*drive.File),
-		nextFileID: 1,
+	svc, err := fake.Service(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create Drive service: %v", err)
 	}
-}
 
-func (m *mockDriveService) setListResponse(files []*drive.File, err error) {
-	m.listResponse = &drive.FileList{Files: files}
-	m.listError = err
+	folderID := fake.AddFolder("Blue Prince", "")
+	vaultPath := t.TempDir()
+	return NewStore(context.Background(), svc, vaultPath, t.TempDir(), folderID), fake, folderID
 }
 
-func (m *mockDriveService) setCreateError(err error) {
-	m.createError = err
-}
+// testPNG returns an encoded PNG whose content depends on seed
+func testPNG(t *testing.T, seed uint8) []byte {
+	t.Helper()
 
-// Mock drive.FilesService
-type mockFilesService struct {
-	mock *mockDriveService
+	img := image.NewGray(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x*y) ^ seed})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode png: %v", err)
+	}
+	return buf.Bytes()
 }
 
-func (m *mockFilesService) List() *drive.FilesListCall {
-	return &drive.FilesListCall{}
+func childNames(files []drivetest.File) []string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name
+	}
+	return names
 }
 
-func (m *mockFilesService) Create(file *drive.File) *drive.FilesCreateCall {
-	return &drive.FilesCreateCall{}
+func TestGoogleDrive_GetFiles_All(t *testing.T) {
+	gd, fake, folderID := newTestStore(t)
+
+	parlor := testPNG(t, 1)
+	study := testPNG(t, 2)
+	fake.AddFile("parlor.png", folderID, "image/png", parlor)
+	fake.AddFile("study.png", folderID, "image/png", study)
+	fake.AddFolder("some_subfolder", folderID)
+	fake.AddFile("elsewhere.png", "", "image/png", testPNG(t, 3))
+
+	files, err := gd.GetFiles("")
+	if err != nil {
+		t.Fatalf("GetFiles() failed: %v", err)
+	}
+
+	sort.Strings(files)
+	if len(files) != 2 || files[0] != "parlor.png" || files[1] != "study.png" {
+		t.Fatalf("GetFiles() should return both screenshots, got: %v", files)
+	}
+
+	// Content lands in the vault's screenshots dir
+	for name, expected := range map[string][]byte{"parlor.png": parlor, "study.png": study} {
+		data, err := os.ReadFile(filepath.Join(gd.VaultPath, vault.SCREENSHOT_DIR, name))
+		if err != nil {
+			t.Fatalf("Expected %s in vault: %v", name, err)
+		}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("Content of %s does not match Drive content", name)
+		}
+	}
+
+	// Downloaded files are archived in Drive; folders are left alone
+	remaining := childNames(fake.Children(folderID))
+	if len(remaining) != 2 || remaining[0] != archive_dir || remaining[1] != "some_subfolder" {
+		t.Errorf("Only folders should remain in the screenshot folder, got: %v", remaining)
+	}
+	archive, ok := fake.FindChild(folderID, archive_dir)
+	if !ok {
+		t.Fatalf("Archive folder %s should have been created", archive_dir)
+	}
+	archived := childNames(fake.Children(archive.ID))
+	if len(archived) != 2 {
+		t.Errorf("Both screenshots should be archived, got: %v", archived)
+	}
+
+	// Both imports are recorded in the manifest
+	m, err := screenshots.LoadManifest(gd.VaultPath)
+	if err != nil {
+		t.Fatalf("LoadManifest() failed: %v", err)
+	}
+	if len(m.Entries) != 2 {
+		t.Errorf("Manifest should track 2 screenshots, got %d", len(m.Entries))
+	}
 }
 
-// Since we can't easily mock the Google Drive API calls directly,
-// we'll test error handling when no client is provided
+func TestGoogleDrive_GetFiles_ByName(t *testing.T) {
+	gd, fake, folderID := newTestStore(t)
 
-func TestGoogleDrive_GetFile(t *testing.T) {
-	gd := &GoogleDrive{
-		VaultPath:      "/test/vault",
-		FolderID:       "test_folder_id",
-		FolderName:     "test_folder",
-		ScreenshotsDir: "/test/vault/screenshots",
-		Client:         nil, // We're not testing API calls here
+	fake.AddFile("Blue Prince's Room.png", folderID, "image/png", testPNG(t, 1))
+	fake.AddFile("other.png", folderID, "image/png", testPNG(t, 2))
+
+	files, err := gd.GetFiles("Blue Prince's Room.png")
+	if err != nil {
+		t.Fatalf("GetFiles() failed for name with apostrophe: %v", err)
+	}
+	if len(files) != 1 || files[0] != "Blue Prince's Room.png" {
+		t.Fatalf("GetFiles() should return only the named file, got: %v", files)
 	}
 
-	// Test that GetFile method exists and has correct signature
-	// Since we don't have a real Google Drive client, this will fail
-	result, err := gd.GetFiles("test.jpg")
+	if _, err := os.Stat(filepath.Join(gd.VaultPath, vault.SCREENSHOT_DIR, "other.png")); !os.IsNotExist(err) {
+		t.Error("GetFiles() with a name should not download other files")
+	}
+	if _, ok := fake.FindChild(folderID, "other.png"); !ok {
+		t.Error("GetFiles() with a name should not archive other files")
+	}
+}
 
-	// Without a proper client, this should return an error
+func TestGoogleDrive_GetFiles_NotFound(t *testing.T) {
+	gd, _, _ := newTestStore(t)
+
+	result, err := gd.GetFiles("missing.png")
 	if err == nil {
-		t.Error("GetFile() should return error when client is nil")
+		t.Error("GetFiles() should return error when the file does not exist")
 	}
 	if result != nil {
-		t.Errorf("GetFile() should return nil when error occurs, got: %v", result)
+		t.Errorf("GetFiles() should return nil when error occurs, got: %v", result)
 	}
 }
 
-func TestGoogleDrive_ListFiles(t *testing.T) {
-	gd := &GoogleDrive{
-		VaultPath:      "/test/vault",
-		FolderID:       "test_folder_id",
-		FolderName:     "test_folder",
-		ScreenshotsDir: "/test/vault/screenshots",
-		Client:         nil, // We're not testing API calls here
+func TestGoogleDrive_GetFiles_SkipsDuplicates(t *testing.T) {
+	gd, fake, folderID := newTestStore(t)
+
+	content := testPNG(t, 1)
+	fake.AddFile("parlor.png", folderID, "image/png", content)
+	if _, err := gd.GetFiles(""); err != nil {
+		t.Fatalf("First GetFiles() failed: %v", err)
 	}
 
-	// Test that ListFiles method exists and has correct signature
-	// Since we don't have a real Google Drive client, this will fail
-	result, err := gd.ListFiles()
+	// The same screenshot uploaded again under a new name
+	fake.AddFile("parlor (1).png", folderID, "image/png", content)
+	files, err := gd.GetFiles("")
+	if err != nil {
+		t.Fatalf("Second GetFiles() failed: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Duplicate screenshot should not be imported, got: %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(gd.VaultPath, vault.SCREENSHOT_DIR, "parlor (1).png")); !os.IsNotExist(err) {
+		t.Error("Duplicate screenshot should not be written to the vault")
+	}
 
-	// Without a proper client, this should return an error
-	if err == nil {
-		t.Error("ListFiles() should return error when client is nil")
+	// Duplicates are still archived so they aren't downloaded again
+	if _, ok := fake.FindChild(folderID, "parlor (1).png"); ok {
+		t.Error("Duplicate screenshot should be archived in Drive")
 	}
-	if result != nil {
-		t.Errorf("ListFiles() should return nil when error occurs, got: %v", result)
+}
+
+func TestGoogleDrive_ListFiles(t *testing.T) {
+	gd, fake, folderID := newTestStore(t)
+
+	fake.AddFile("a.png", folderID, "image/png", testPNG(t, 1))
+	fake.AddFile("b.jpg", folderID, "image/jpeg", []byte("jpeg"))
+	fake.AddFolder(archive_dir, folderID)
+	trashed := fake.AddFile("trashed.png", folderID, "image/png", testPNG(t, 2))
+	fake.Trash(trashed)
+	fake.AddFile("elsewhere.png", "", "image/png", testPNG(t, 3))
+
+	result, err := gd.ListFiles()
+	if err != nil {
+		t.Fatalf("ListFiles() failed: %v", err)
+	}
+
+	sort.Strings(result)
+	if len(result) != 2 || result[0] != "a.png" || result[1] != "b.jpg" {
+		t.Errorf("ListFiles() should only return untrashed files in the folder, got: %v", result)
 	}
 }
 
 func TestGoogleDrive_MoveFile(t *testing.T) {
-	gd := &GoogleDrive{
-		VaultPath:      "/test/vault",
-		FolderID:       "test_folder_id",
-		FolderName:     "test_folder",
-		ScreenshotsDir: "/test/vault/screenshots",
-		Client:         nil, // We're not testing API calls here
+	gd, fake, folderID := newTestStore(t)
+
+	first := fake.AddFile("first.png", folderID, "image/png", testPNG(t, 1))
+	second := fake.AddFile("second.png", folderID, "image/png", testPNG(t, 2))
+
+	if err := gd.MoveFile(first, "new_location"); err != nil {
+		t.Fatalf("MoveFile() failed: %v", err)
+	}
+	if err := gd.MoveFile(second, "new_location"); err != nil {
+		t.Fatalf("MoveFile() failed: %v", err)
 	}
 
-	// Test that MoveFile method exists and has correct signature
-	// Since we don't have a real Google Drive client, this will fail
-	err := gd.MoveFile("test.jpg", "new_location")
+	// The destination folder is created once and reused
+	var folders []drivetest.File
+	for _, f := range fake.Children(folderID) {
+		if f.MimeType == drivetest.FolderMimeType {
+			folders = append(folders, f)
+		}
+	}
+	if len(folders) != 1 || folders[0].Name != "new_location" {
+		t.Fatalf("Expected a single 'new_location' folder, got: %v", childNames(folders))
+	}
 
-	// Without a proper client, this should return an error
-	if err == nil {
-		t.Error("MoveFile() should return error when client is nil")
+	moved := childNames(fake.Children(folders[0].ID))
+	if len(moved) != 2 || moved[0] != "first.png" || moved[1] != "second.png" {
+		t.Errorf("Both files should be in the destination, got: %v", moved)
+	}
+
+	f, _ := fake.File(first)
+	if len(f.Parents) != 1 || f.Parents[0] != folders[0].ID {
+		t.Errorf("Moved file should only have the destination as parent, got: %v", f.Parents)
+	}
+}
+
+func TestGoogleDrive_MoveFile_MissingFile(t *testing.T) {
+	gd, _, _ := newTestStore(t)
+
+	if err := gd.MoveFile("no_such_id", "new_location"); err == nil {
+		t.Error("MoveFile() should return error when the file does not exist")
+	}
+}
+
+func TestGoogleDrive_FindOrCreateFolder(t *testing.T) {
+	gd, fake, _ := newTestStore(t)
+
+	id, err := gd.FindOrCreateFolder("Prince's Screens")
+	if err != nil {
+		t.Fatalf("FindOrCreateFolder() failed: %v", err)
+	}
+	f, ok := fake.File(id)
+	if !ok || f.Name != "Prince's Screens" || f.MimeType != drivetest.FolderMimeType {
+		t.Fatalf("FindOrCreateFolder() should create the folder, got: %+v", f)
+	}
+
+	again, err := gd.FindOrCreateFolder("Prince's Screens")
+	if err != nil {
+		t.Fatalf("FindOrCreateFolder() failed on second call: %v", err)
+	}
+	if again != id {
+		t.Errorf("FindOrCreateFolder() should find the existing folder %s, got %s", id, again)
 	}
 }
 
@@ -137,20 +294,6 @@ func TestGoogleDrive_Structure(t *testing.T) {
 	}
 }
 
-// Test that GoogleDrive implements the Store interface
-func TestGoogleDrive_ImplementsStoreInterface(t *testing.T) {
-	var gd interface{} = &GoogleDrive{}
-
-	// Check if GoogleDrive has the required methods for Store interface
-	if _, ok := gd.(interface {
-		GetFile(filename, dirname string) (any, error)
-		ListFiles(dirname string) ([]string, error)
-		MoveFile(filename, destination string) error
-	}); !ok {
-		t.Error("GoogleDrive should implement Store interface methods")
-	}
-}
-
 // Integration test structure validation
 func TestGoogleDriveIntegration_FieldTypes(t *testing.T) {
 	gd := &GoogleDrive{}
@@ -162,30 +305,3 @@ func TestGoogleDriveIntegration_FieldTypes(t *testing.T) {
 	var _ string = gd.ScreenshotsDir
 	var _ *drive.Service = gd.Client
 }
-
-// Test error conditions that don't require API calls
-func TestGoogleDrive_ErrorHandling(t *testing.T) {
-	gd := &GoogleDrive{
-		VaultPath:      "",
-		FolderID:       "",
-		FolderName:     "",
-		ScreenshotsDir: "",
-		Client:         nil,
-	}
-
-	// Test with empty values - methods should still work since they're placeholders
-	_, err := gd.GetFiles("")
-	if err != nil {
-		t.Errorf("GetFile() with empty parameters should not error in placeholder, got: %v", err)
-	}
-
-	_, err = gd.ListFiles()
-	if err != nil {
-		t.Errorf("ListFiles() with empty parameter should not error in placeholder, got: %v", err)
-	}
-
-	err = gd.MoveFile("", "")
-	if err != nil {
-		t.Errorf("MoveFile() with empty parameters should not error in placeholder, got: %v", err)
-	}
-}
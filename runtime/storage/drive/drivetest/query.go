@@ -0,0 +1,177 @@
+package drivetest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// matcher reports whether a file satisfies a parsed query
+type matcher func(f *File) bool
+
+// token is a lexical element of a Drive query
+type token struct {
+	literal bool // true for quoted string literals
+	value   string
+}
+
+// parseQuery parses the subset of the Drive query language that the drive package emits:
+// clauses joined by "and", where each clause is one of
+//
+//	name = 'x'
+//	'id' in parents
+//	trashed = true|false
+//	mimeType = 'x' | mimeType != 'x'
+//	modifiedTime > 'ts' | modifiedTime < 'ts'
+//
+// Anything else is rejected so tests fail loudly when the real API would interpret a query differently.
+func parseQuery(q string) (matcher, error) {
+	tokens, err := tokenize(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var clauses []matcher
+	for len(tokens) > 0 {
+		clause, rest, err := parseClause(tokens)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+		tokens = rest
+
+		if len(tokens) == 0 {
+			break
+		}
+		if tokens[0].literal || tokens[0].value != "and" {
+			return nil, fmt.Errorf("expected 'and', got %q", tokens[0].value)
+		}
+		tokens = tokens[1:]
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("dangling 'and'")
+		}
+	}
+
+	return func(f *File) bool {
+		for _, clause := range clauses {
+			if !clause(f) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func parseClause(tokens []token) (matcher, []token, error) {
+	if len(tokens) < 3 {
+		return nil, nil, fmt.Errorf("incomplete clause near %v", tokens)
+	}
+	left, op, right := tokens[0], tokens[1], tokens[2]
+	rest := tokens[3:]
+
+	// 'id' in parents
+	if left.literal {
+		if op.literal || op.value != "in" || right.literal || right.value != "parents" {
+			return nil, nil, fmt.Errorf("unsupported clause: '%s' %s %s", left.value, op.value, right.value)
+		}
+		return func(f *File) bool { return hasParent(f, left.value) }, rest, nil
+	}
+
+	switch left.value {
+	case "name":
+		if op.value != "=" || !right.literal {
+			return nil, nil, fmt.Errorf("unsupported name clause: %s %s", op.value, right.value)
+		}
+		return func(f *File) bool { return f.Name == right.value }, rest, nil
+
+	case "trashed":
+		if op.value != "=" || right.literal || (right.value != "true" && right.value != "false") {
+			return nil, nil, fmt.Errorf("unsupported trashed clause: %s %s", op.value, right.value)
+		}
+		want := right.value == "true"
+		return func(f *File) bool { return f.Trashed == want }, rest, nil
+
+	case "mimeType":
+		if !right.literal {
+			return nil, nil, fmt.Errorf("mimeType must be compared to a string literal")
+		}
+		switch op.value {
+		case "=":
+			return func(f *File) bool { return f.MimeType == right.value }, rest, nil
+		case "!=":
+			return func(f *File) bool { return f.MimeType != right.value }, rest, nil
+		}
+		return nil, nil, fmt.Errorf("unsupported mimeType operator %s", op.value)
+
+	case "modifiedTime":
+		if !right.literal {
+			return nil, nil, fmt.Errorf("modifiedTime must be compared to a string literal")
+		}
+		t, err := time.Parse(time.RFC3339, right.value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid modifiedTime: %w", err)
+		}
+		switch op.value {
+		case ">":
+			return func(f *File) bool { return f.ModifiedTime.After(t) }, rest, nil
+		case "<":
+			return func(f *File) bool { return f.ModifiedTime.Before(t) }, rest, nil
+		}
+		return nil, nil, fmt.Errorf("unsupported modifiedTime operator %s", op.value)
+	}
+
+	return nil, nil, fmt.Errorf("unsupported field %q", left.value)
+}
+
+// tokenize splits a query into words, operators and unescaped string literals
+func tokenize(q string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(q); {
+		c := q[i]
+		switch {
+		case c == ' ':
+			i++
+
+		case c == '\'':
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(q) {
+				if q[i] == '\\' {
+					if i+1 >= len(q) {
+						return nil, fmt.Errorf("dangling escape in %q", q)
+					}
+					sb.WriteByte(q[i+1])
+					i += 2
+					continue
+				}
+				if q[i] == '\'' {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteByte(q[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string literal in %q", q)
+			}
+			tokens = append(tokens, token{literal: true, value: sb.String()})
+
+		case strings.ContainsRune("=!<>", rune(c)):
+			start := i
+			for i < len(q) && strings.ContainsRune("=!<>", rune(q[i])) {
+				i++
+			}
+			tokens = append(tokens, token{value: q[start:i]})
+
+		default:
+			start := i
+			for i < len(q) && q[i] != ' ' && q[i] != '\'' && !strings.ContainsRune("=!<>", rune(q[i])) {
+				i++
+			}
+			tokens = append(tokens, token{value: q[start:i]})
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,378 @@
+// Package drivetest provides an in-process fake of the Google Drive v3 endpoints used by the drive package,
+// so code that talks to Drive can be tested hermetically.
+package drivetest
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+const (
+	FolderMimeType = "application/vnd.google-apps.folder"
+
+	apiPrefix    = "/drive/v3/files"
+	uploadPrefix = "/upload/drive/v3/files"
+)
+
+// File is a file or folder stored by the fake server
+type File struct {
+	ID           string
+	Name         string
+	MimeType     string
+	Parents      []string
+	Trashed      bool
+	CreatedTime  time.Time
+	ModifiedTime time.Time
+	Content      []byte
+}
+
+// Server is a fake Drive API backed by an in-memory file table.
+// It supports files.list (with q parsing), files.get (metadata and alt=media),
+// files.create (metadata only and multipart uploads), files.update (add/removeParents, name, trashed and content)
+// and files.delete.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	files  map[string]*File
+	nextID int
+	now    func() time.Time
+}
+
+// NewServer starts a fake Drive server. Callers must Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		files: make(map[string]*File),
+		now:   time.Now,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Service returns a Drive client wired to the fake server through option.WithEndpoint
+func (s *Server) Service(ctx context.Context) (*drive.Service, error) {
+	return drive.NewService(ctx,
+		option.WithEndpoint(s.URL+"/drive/v3/"),
+		option.WithHTTPClient(s.Client()),
+	)
+}
+
+// AddFolder creates a folder and returns its ID. An empty parentID creates a top-level folder.
+func (s *Server) AddFolder(name, parentID string) string {
+	return s.AddFile(name, parentID, FolderMimeType, nil)
+}
+
+// AddFile creates a file with the given content and returns its ID
+func (s *Server) AddFile(name, parentID, mimeType string, content []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := &File{Name: name, MimeType: mimeType, Content: content}
+	if parentID != "" {
+		f.Parents = []string{parentID}
+	}
+	return s.insert(f).ID
+}
+
+// File returns a copy of the file with the given ID
+func (s *Server) File(id string) (File, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[id]
+	if !ok {
+		return File{}, false
+	}
+	return *f, true
+}
+
+// Trash moves a file to the trash
+func (s *Server) Trash(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f, ok := s.files[id]; ok {
+		f.Trashed = true
+	}
+}
+
+// Children returns copies of the untrashed files directly inside parentID, sorted by name
+func (s *Server) Children(parentID string) []File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var children []File
+	for _, f := range s.files {
+		if !f.Trashed && hasParent(f, parentID) {
+			children = append(children, *f)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	return children
+}
+
+// FindChild returns the untrashed file called name directly inside parentID
+func (s *Server) FindChild(parentID, name string) (File, bool) {
+	for _, f := range s.Children(parentID) {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+// insert assigns an ID to f and stores it. Callers must hold s.mu.
+func (s *Server) insert(f *File) *File {
+	s.nextID++
+	f.ID = fmt.Sprintf("file%d", s.nextID)
+	f.CreatedTime = s.now()
+	f.ModifiedTime = f.CreatedTime
+	s.files[f.ID] = f
+	return f
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := r.URL.Path
+	switch {
+	case path == apiPrefix && r.Method == http.MethodGet:
+		s.list(w, r)
+	case path == apiPrefix && r.Method == http.MethodPost:
+		s.create(w, r, false)
+	case path == uploadPrefix && r.Method == http.MethodPost:
+		s.create(w, r, true)
+	case strings.HasPrefix(path, apiPrefix+"/"):
+		id := strings.TrimPrefix(path, apiPrefix+"/")
+		switch r.Method {
+		case http.MethodGet:
+			s.get(w, r, id)
+		case http.MethodPatch:
+			s.update(w, r, id, false)
+		case http.MethodDelete:
+			s.delete(w, id)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "unsupported method %s", r.Method)
+		}
+	case strings.HasPrefix(path, uploadPrefix+"/") && r.Method == http.MethodPatch:
+		s.update(w, r, strings.TrimPrefix(path, uploadPrefix+"/"), true)
+	default:
+		writeError(w, http.StatusNotFound, "unsupported endpoint %s %s", r.Method, path)
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	match, err := parseQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid q: %v", err)
+		return
+	}
+
+	var matched []*File
+	for _, f := range s.files {
+		if match(f) {
+			matched = append(matched, f)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+
+	files := make([]*drive.File, len(matched))
+	for i, f := range matched {
+		files[i] = toAPI(f)
+	}
+	writeJSON(w, &drive.FileList{Files: files})
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, id string) {
+	f, ok := s.files[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "file not found: %s", id)
+		return
+	}
+
+	if r.URL.Query().Get("alt") == "media" {
+		w.Header().Set("Content-Type", f.MimeType)
+		w.Write(f.Content)
+		return
+	}
+	writeJSON(w, toAPI(f))
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, upload bool) {
+	meta, content, err := readBody(r, upload)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	f := s.insert(&File{
+		Name:     meta.Name,
+		MimeType: meta.MimeType,
+		Parents:  meta.Parents,
+		Content:  content,
+	})
+	writeJSON(w, toAPI(f))
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, id string, upload bool) {
+	f, ok := s.files[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "file not found: %s", id)
+		return
+	}
+
+	meta, content, err := readBody(r, upload)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	if meta.Name != "" {
+		f.Name = meta.Name
+	}
+	if meta.Trashed {
+		f.Trashed = true
+	}
+	if upload {
+		f.Content = content
+	}
+
+	params := r.URL.Query()
+	for _, parent := range splitIDs(params.Get("removeParents")) {
+		f.Parents = removeString(f.Parents, parent)
+	}
+	for _, parent := range splitIDs(params.Get("addParents")) {
+		if !hasParent(f, parent) {
+			f.Parents = append(f.Parents, parent)
+		}
+	}
+	f.ModifiedTime = s.now()
+
+	writeJSON(w, toAPI(f))
+}
+
+func (s *Server) delete(w http.ResponseWriter, id string) {
+	if _, ok := s.files[id]; !ok {
+		writeError(w, http.StatusNotFound, "file not found: %s", id)
+		return
+	}
+	delete(s.files, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readBody decodes the file metadata and, for uploads, the media content of a create or update request
+func readBody(r *http.Request, upload bool) (*drive.File, []byte, error) {
+	meta := &drive.File{}
+	if !upload {
+		if err := json.NewDecoder(r.Body).Decode(meta); err != nil && err != io.EOF {
+			return nil, nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+		return meta, nil, nil
+	}
+
+	if uploadType := r.URL.Query().Get("uploadType"); uploadType != "multipart" {
+		return nil, nil, fmt.Errorf("unsupported uploadType %q", uploadType)
+	}
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid content type: %w", err)
+	}
+
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	metaPart, err := reader.NextPart()
+	if err != nil {
+		return nil, nil, fmt.Errorf("missing metadata part: %w", err)
+	}
+	if err := json.NewDecoder(metaPart).Decode(meta); err != nil {
+		return nil, nil, fmt.Errorf("invalid metadata part: %w", err)
+	}
+	mediaPart, err := reader.NextPart()
+	if err != nil {
+		return nil, nil, fmt.Errorf("missing media part: %w", err)
+	}
+	content, err := io.ReadAll(mediaPart)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read media part: %w", err)
+	}
+	if meta.MimeType == "" {
+		meta.MimeType = mediaPart.Header.Get("Content-Type")
+	}
+	return meta, content, nil
+}
+
+func toAPI(f *File) *drive.File {
+	apiFile := &drive.File{
+		Id:           f.ID,
+		Name:         f.Name,
+		MimeType:     f.MimeType,
+		Parents:      f.Parents,
+		Trashed:      f.Trashed,
+		CreatedTime:  f.CreatedTime.UTC().Format(time.RFC3339Nano),
+		ModifiedTime: f.ModifiedTime.UTC().Format(time.RFC3339Nano),
+		Size:         int64(len(f.Content)),
+	}
+	if f.MimeType != FolderMimeType {
+		sum := md5.Sum(f.Content)
+		apiFile.Md5Checksum = hex.EncodeToString(sum[:])
+	}
+	return apiFile
+}
+
+func hasParent(f *File, parentID string) bool {
+	for _, parent := range f.Parents {
+		if parent == parentID {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, target string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != target {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func splitIDs(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError responds in the same shape as real Drive errors so googleapi.Error decodes it
+func writeError(w http.ResponseWriter, code int, format string, args ...any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": fmt.Sprintf(format, args...),
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package drive
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Query composes a Google Drive files.list search query (the `q` param) from typed clauses.
+// Every value is escaped, so names containing quotes or backslashes can't break out of their string literal.
+// See https://developers.google.com/drive/api/guides/search-files
+type Query struct {
+	clauses []string
+}
+
+// NewQuery returns an empty query. An empty query matches everything.
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Name matches files whose name is exactly name
+func (q *Query) Name(name string) *Query {
+	return q.add(fmt.Sprintf("name = %s", quote(name)))
+}
+
+// InParents matches files directly inside the folder with the given ID
+func (q *Query) InParents(folderID string) *Query {
+	return q.add(fmt.Sprintf("%s in parents", quote(folderID)))
+}
+
+// Trashed matches files by whether they are in the trash
+func (q *Query) Trashed(trashed bool) *Query {
+	return q.add(fmt.Sprintf("trashed = %t", trashed))
+}
+
+// MimeType matches files with exactly the given MIME type
+func (q *Query) MimeType(mimeType string) *Query {
+	return q.add(fmt.Sprintf("mimeType = %s", quote(mimeType)))
+}
+
+// NotMimeType excludes files with the given MIME type
+func (q *Query) NotMimeType(mimeType string) *Query {
+	return q.add(fmt.Sprintf("mimeType != %s", quote(mimeType)))
+}
+
+// ModifiedAfter matches files modified strictly after t
+func (q *Query) ModifiedAfter(t time.Time) *Query {
+	return q.add(fmt.Sprintf("modifiedTime > %s", quote(t.UTC().Format(time.RFC3339))))
+}
+
+// ModifiedBefore matches files modified strictly before t
+func (q *Query) ModifiedBefore(t time.Time) *Query {
+	return q.add(fmt.Sprintf("modifiedTime < %s", quote(t.UTC().Format(time.RFC3339))))
+}
+
+// String renders the query, joining all clauses with "and"
+func (q *Query) String() string {
+	return strings.Join(q.clauses, " and ")
+}
+
+func (q *Query) add(clause string) *Query {
+	q.clauses = append(q.clauses, clause)
+	return q
+}
+
+// queryEscaper escapes the characters that are special inside a Drive query string literal
+var queryEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// quote wraps value in single quotes, escaping it for use in a Drive query
+func quote(value string) string {
+	return "'" + queryEscaper.Replace(value) + "'"
+}
@@ -0,0 +1,122 @@
+package drive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuery_String(t *testing.T) {
+	modified := time.Date(2025, 4, 12, 18, 30, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		query    *Query
+		expected string
+	}{
+		{
+			name:     "empty query",
+			query:    NewQuery(),
+			expected: "",
+		},
+		{
+			name:     "name only",
+			query:    NewQuery().Name("parlor.png"),
+			expected: "name = 'parlor.png'",
+		},
+		{
+			name:     "name with apostrophe",
+			query:    NewQuery().Name("Blue Prince's Room.png"),
+			expected: `name = 'Blue Prince\'s Room.png'`,
+		},
+		{
+			name:     "name with backslash",
+			query:    NewQuery().Name(`C:\screens\a.png`),
+			expected: `name = 'C:\\screens\\a.png'`,
+		},
+		{
+			name:     "injection attempt stays inside the literal",
+			query:    NewQuery().Name("x' or name != '"),
+			expected: `name = 'x\' or name != \''`,
+		},
+		{
+			name:     "backslash before quote",
+			query:    NewQuery().Name(`a\'b`),
+			expected: `name = 'a\\\'b'`,
+		},
+		{
+			name:     "in parents",
+			query:    NewQuery().InParents("folder123"),
+			expected: "'folder123' in parents",
+		},
+		{
+			name:     "trashed false",
+			query:    NewQuery().Trashed(false),
+			expected: "trashed = false",
+		},
+		{
+			name:     "trashed true",
+			query:    NewQuery().Trashed(true),
+			expected: "trashed = true",
+		},
+		{
+			name:     "mime type",
+			query:    NewQuery().MimeType(FOLDER_MIME_TYPE),
+			expected: "mimeType = 'application/vnd.google-apps.folder'",
+		},
+		{
+			name:     "not mime type",
+			query:    NewQuery().NotMimeType(FOLDER_MIME_TYPE),
+			expected: "mimeType != 'application/vnd.google-apps.folder'",
+		},
+		{
+			name:     "modified after",
+			query:    NewQuery().ModifiedAfter(modified),
+			expected: "modifiedTime > '2025-04-12T18:30:05Z'",
+		},
+		{
+			name:     "modified before converts to UTC",
+			query:    NewQuery().ModifiedBefore(modified.In(time.FixedZone("PDT", -7*60*60))),
+			expected: "modifiedTime < '2025-04-12T18:30:05Z'",
+		},
+		{
+			name:     "list files in folder",
+			query:    NewQuery().InParents("folder123").Trashed(false).NotMimeType(FOLDER_MIME_TYPE),
+			expected: "'folder123' in parents and trashed = false and mimeType != 'application/vnd.google-apps.folder'",
+		},
+		{
+			name:     "find subfolder",
+			query:    NewQuery().Name("Prince's Archive").MimeType(FOLDER_MIME_TYPE).InParents("root'id").Trashed(false),
+			expected: `name = 'Prince\'s Archive' and mimeType = 'application/vnd.google-apps.folder' and 'root\'id' in parents and trashed = false`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.query.String(); got != tc.expected {
+				t.Errorf("Query.String() = %q, expected %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"", "''"},
+		{"plain", "'plain'"},
+		{"it's", `'it\'s'`},
+		{`back\slash`, `'back\\slash'`},
+		{`'`, `'\''`},
+		{`\`, `'\\'`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			if got := quote(tc.value); got != tc.expected {
+				t.Errorf("quote(%q) = %q, expected %q", tc.value, got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package drive
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// ListTree lists every file below dir, a slash separated folder path relative to the configured Google Drive folder
+func (g *GoogleDrive) ListTree(dir string) (map[string]storage.RemoteFile, error) {
+	tree := make(map[string]storage.RemoteFile)
+
+	dirID, found, err := g.resolveFolder(splitPath(dir), false)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return tree, nil
+	}
+
+	if err := g.walk(dirID, "", tree); err != nil {
+		return nil, fmt.Errorf("failed to list '%s': %w", dir, err)
+	}
+	return tree, nil
+}
+
+// ReadFile downloads the file at filePath inside dir
+func (g *GoogleDrive) ReadFile(dir, filePath string) ([]byte, error) {
+	fileID, found, err := g.resolveFile(dir, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("file '%s' not found in '%s'", filePath, dir)
+	}
+	return g.download(fileID)
+}
+
+// WriteFile uploads data to filePath inside dir, replacing the content of an existing file so its ID is kept
+func (g *GoogleDrive) WriteFile(dir, filePath string, data []byte) error {
+	segments := append(splitPath(dir), splitPath(path.Dir(filePath))...)
+	parentID, _, err := g.resolveFolder(segments, true)
+	if err != nil {
+		return err
+	}
+
+	name := path.Base(filePath)
+	fileID, found, err := g.findChild(parentID, name, false)
+	if err != nil {
+		return err
+	}
+	if !found {
+		if err := g.upload(parentID, name, data); err != nil {
+			return fmt.Errorf("failed to upload '%s': %w", filePath, err)
+		}
+		return nil
+	}
+
+	if _, err := g.Client.Files.Update(fileID, &drive.File{}).Media(bytes.NewReader(data)).Do(); err != nil {
+		return fmt.Errorf("failed to update '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// DeleteFile moves the file at filePath inside dir to the Drive trash, so it can still be recovered by hand
+func (g *GoogleDrive) DeleteFile(dir, filePath string) error {
+	fileID, found, err := g.resolveFile(dir, filePath)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+
+	if _, err := g.Client.Files.Update(fileID, &drive.File{Trashed: true}).Do(); err != nil {
+		return fmt.Errorf("failed to delete '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// walk adds every file below folderID to tree, prefixing paths with prefix
+func (g *GoogleDrive) walk(folderID, prefix string, tree map[string]storage.RemoteFile) error {
+	query := NewQuery().InParents(folderID).Trashed(false)
+
+	call := g.Client.Files.List().Q(query.String()).PageSize(max_page_size).
+		Fields("nextPageToken", "files(id, name, mimeType, md5Checksum, modifiedTime)")
+	for {
+		result, err := call.Do()
+		if err != nil {
+			return err
+		}
+		for _, file := range result.Files {
+			filePath := path.Join(prefix, file.Name)
+			if file.MimeType == FOLDER_MIME_TYPE {
+				if err := g.walk(file.Id, filePath, tree); err != nil {
+					return err
+				}
+				continue
+			}
+
+			modified, _ := time.Parse(time.RFC3339, file.ModifiedTime)
+			tree[filePath] = storage.RemoteFile{
+				Path:         filePath,
+				MD5:          file.Md5Checksum,
+				ModifiedTime: modified,
+			}
+		}
+		if result.NextPageToken == "" {
+			return nil
+		}
+		call.PageToken(result.NextPageToken)
+	}
+}
+
+// resolveFile finds the ID of the file at filePath inside dir without creating anything
+func (g *GoogleDrive) resolveFile(dir, filePath string) (string, bool, error) {
+	segments := append(splitPath(dir), splitPath(path.Dir(filePath))...)
+	parentID, found, err := g.resolveFolder(segments, false)
+	if err != nil || !found {
+		return "", false, err
+	}
+	return g.findChild(parentID, path.Base(filePath), false)
+}
+
+// resolveFolder follows segments down from the configured folder, optionally creating missing folders
+func (g *GoogleDrive) resolveFolder(segments []string, create bool) (string, bool, error) {
+	folderID := g.FolderID
+	for _, segment := range segments {
+		if create {
+			id, err := g.findOrCreateFolderIn(folderID, segment)
+			if err != nil {
+				return "", false, err
+			}
+			folderID = id
+			continue
+		}
+
+		id, found, err := g.findChild(folderID, segment, true)
+		if err != nil || !found {
+			return "", false, err
+		}
+		folderID = id
+	}
+	return folderID, true, nil
+}
+
+// findChild looks up a folder or file called name directly inside parentID
+func (g *GoogleDrive) findChild(parentID, name string, folder bool) (string, bool, error) {
+	query := NewQuery().Name(name).InParents(parentID).Trashed(false)
+	if folder {
+		query.MimeType(FOLDER_MIME_TYPE)
+	} else {
+		query.NotMimeType(FOLDER_MIME_TYPE)
+	}
+
+	result, err := g.Client.Files.List().Q(query.String()).Fields("files(id)").Do()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to search for '%s': %w", name, err)
+	}
+	if len(result.Files) == 0 {
+		return "", false, nil
+	}
+	return result.Files[0].Id, true, nil
+}
+
+// splitPath splits a slash separated path into its non-empty segments
+func splitPath(p string) []string {
+	var segments []string
+	for _, segment := range strings.Split(p, "/") {
+		if segment != "" && segment != "." {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
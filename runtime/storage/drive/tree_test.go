@@ -0,0 +1,90 @@
+package drive
+
+import (
+	"testing"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+func TestGoogleDrive_ListTree_Missing(t *testing.T) {
+	gd, _, _ := newTestStore(t)
+
+	tree, err := gd.ListTree("vault_sync")
+	if err != nil {
+		t.Fatalf("ListTree() failed: %v", err)
+	}
+	if len(tree) != 0 {
+		t.Errorf("ListTree() of a missing dir should be empty, got: %v", tree)
+	}
+}
+
+func TestGoogleDrive_WriteAndReadTree(t *testing.T) {
+	gd, fake, folderID := newTestStore(t)
+
+	if err := gd.WriteFile("vault_sync", "rooms/parlor.md", []byte("# Parlor")); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	if err := gd.WriteFile("vault_sync", "people/simon's notes.md", []byte("# Simon")); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+
+	tree, err := gd.ListTree("vault_sync")
+	if err != nil {
+		t.Fatalf("ListTree() failed: %v", err)
+	}
+	if len(tree) != 2 {
+		t.Fatalf("ListTree() should return 2 files, got: %v", tree)
+	}
+	parlor, ok := tree["rooms/parlor.md"]
+	if !ok {
+		t.Fatalf("ListTree() should key files by nested path, got: %v", tree)
+	}
+	if parlor.MD5 != utils.MD5Bytes([]byte("# Parlor")) {
+		t.Errorf("ListTree() MD5 = %s, expected checksum of the content", parlor.MD5)
+	}
+
+	// Overwriting keeps a single file with the new content
+	if err := gd.WriteFile("vault_sync", "rooms/parlor.md", []byte("# Parlor v2")); err != nil {
+		t.Fatalf("WriteFile() overwrite failed: %v", err)
+	}
+	syncDir, _ := fake.FindChild(folderID, "vault_sync")
+	rooms, _ := fake.FindChild(syncDir.ID, "rooms")
+	if children := fake.Children(rooms.ID); len(children) != 1 {
+		t.Errorf("Overwrite should not create a second file, got: %v", childNames(children))
+	}
+
+	data, err := gd.ReadFile("vault_sync", "rooms/parlor.md")
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	if string(data) != "# Parlor v2" {
+		t.Errorf("ReadFile() = %q, expected the overwritten content", data)
+	}
+}
+
+func TestGoogleDrive_DeleteFile(t *testing.T) {
+	gd, _, _ := newTestStore(t)
+
+	if err := gd.WriteFile("vault_sync", "rooms/parlor.md", []byte("# Parlor")); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	if err := gd.DeleteFile("vault_sync", "rooms/parlor.md"); err != nil {
+		t.Fatalf("DeleteFile() failed: %v", err)
+	}
+
+	tree, err := gd.ListTree("vault_sync")
+	if err != nil {
+		t.Fatalf("ListTree() failed: %v", err)
+	}
+	if len(tree) != 0 {
+		t.Errorf("Deleted files should not be listed, got: %v", tree)
+	}
+	if _, err := gd.ReadFile("vault_sync", "rooms/parlor.md"); err == nil {
+		t.Error("ReadFile() should fail for a deleted file")
+	}
+
+	// Deleting a missing file is a no-op
+	if err := gd.DeleteFile("vault_sync", "rooms/parlor.md"); err != nil {
+		t.Errorf("DeleteFile() of a missing file should not fail: %v", err)
+	}
+}
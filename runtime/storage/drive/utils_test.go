@@ -26,7 +26,8 @@ func TestCredsPath(t *testing.T) {
 }
 
 func TestTokenPath(t *testing.T) {
-	path, err := TokenPath("")
+	secretsDir := filepath.Join(t.TempDir(), CONFIG_DIR)
+	path, err := TokenPath(secretsDir)
 	if err != nil {
 		t.Fatalf("TokenPath() failed: %v", err)
 	}
@@ -46,7 +47,8 @@ func TestTokenPath(t *testing.T) {
 }
 
 func TestConfigPath(t *testing.T) {
-	path, err := ConfigPath("")
+	secretsDir := filepath.Join(t.TempDir(), CONFIG_DIR)
+	path, err := ConfigPath(secretsDir)
 	if err != nil {
 		t.Fatalf("ConfigPath() failed: %v", err)
 	}
@@ -180,7 +182,7 @@ func TestLoadDriveConfig(t *testing.T) {
 	}
 
 	// Test LoadDriveConfig
-	loadedConfig, err := LoadDriveConfig(configPath)
+	loadedConfig, err := LoadDriveConfig(configDir)
 	if err != nil {
 		t.Fatalf("LoadDriveConfig() failed: %v", err)
 	}
@@ -0,0 +1,90 @@
+package local
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+)
+
+var (
+	objectsDir   = path.Join(storage.BACKUP_DIR, storage.BACKUP_OBJECTS_DIR)
+	snapshotsDir = path.Join(storage.BACKUP_DIR, storage.BACKUP_SNAPSHOTS_DIR)
+)
+
+// BackupVault copies the given vault subdirs into Root/vault_backups, using the same layout as the Google Drive backend
+func (d *Directory) BackupVault(vaultPath string, dirs []string) (*storage.BackupResult, error) {
+	snapshot, objects, err := storage.NewSnapshot(vaultPath, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := d.ListTree(objectsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup objects: %w", err)
+	}
+
+	result := &storage.BackupResult{Snapshot: snapshot}
+	for sum, data := range objects {
+		if _, ok := existing[sum]; ok {
+			result.Reused++
+			continue
+		}
+		if err := d.WriteFile(objectsDir, sum, data); err != nil {
+			return nil, fmt.Errorf("failed to write backup object: %w", err)
+		}
+		result.Uploaded++
+	}
+
+	// Write the manifest last so a partial backup never produces a snapshot that references missing objects
+	manifest, err := snapshot.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.WriteFile(snapshotsDir, snapshot.FileName(), manifest); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+
+	return result, nil
+}
+
+// RestoreVault copies the newest snapshot in Root/vault_backups into vaultPath
+func (d *Directory) RestoreVault(vaultPath string) (*storage.Snapshot, error) {
+	snapshots, err := d.ListTree(snapshotsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots found in '%s'", storage.BACKUP_DIR)
+	}
+
+	// Snapshot names sort in time order, so the newest is last
+	names := make([]string, 0, len(snapshots))
+	for name := range snapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	newest := names[len(names)-1]
+
+	data, err := d.ReadFile(snapshotsDir, newest)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := storage.ParseSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for relPath, sum := range snapshot.Files {
+		content, err := d.ReadFile(objectsDir, sum)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot '%s' references missing object for '%s': %w", newest, relPath, err)
+		}
+		if err := storage.RestoreFile(vaultPath, relPath, sum, content); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
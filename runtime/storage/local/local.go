@@ -0,0 +1,123 @@
+// Package local implements storage.Store on top of a plain directory, such as a mounted network share
+// or a folder kept in sync by another client. It needs no network access, which also makes it the backend used in tests.
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/screenshots"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+const archive_dir = "downloaded_screenshots"
+
+// Directory is a Store rooted at Root. New screenshots are picked up from the top level of Root,
+// the same way the Google Drive backend reads its configured folder.
+type Directory struct {
+	Root      string
+	VaultPath string
+}
+
+func NewStore(root, vaultPath string) *Directory {
+	return &Directory{
+		Root:      root,
+		VaultPath: vaultPath,
+	}
+}
+
+// GetFiles copies screenshots from Root into the vault and archives them.
+// Files whose content is already in the vault (by SHA-256) are not written again, but are still archived.
+// Returns the names of the newly imported files.
+func (d *Directory) GetFiles(filename string) ([]string, error) {
+	names, err := d.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	if filename != "" {
+		cleanName, err := utils.ValidatePath(filename)
+		if err != nil {
+			return nil, err
+		}
+		names = filterName(names, cleanName)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("file '%s' not found in '%s'", filename, d.Root)
+	}
+
+	files := []string{}
+	for _, name := range names {
+		fullPath := filepath.Join(d.Root, name)
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file '%s': %w", name, err)
+		}
+
+		origin := screenshots.Origin{Source: screenshots.SourceLocalStore}
+		if info, err := os.Stat(fullPath); err == nil {
+			origin.CapturedAt = info.ModTime()
+		}
+
+		saved, err := screenshots.Import(d.VaultPath, name, data, origin)
+		if err != nil {
+			return nil, err
+		}
+		if saved != "" {
+			files = append(files, saved)
+		}
+
+		if err := d.MoveFile(name, archive_dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// ListFiles lists the non-hidden files at the top level of Root
+func (d *Directory) ListFiles() ([]string, error) {
+	entries, err := os.ReadDir(d.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in '%s': %w", d.Root, err)
+	}
+
+	filenames := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || utils.ShouldSkipPath(entry.Name(), entry) {
+			continue
+		}
+		filenames = append(filenames, entry.Name())
+	}
+	return filenames, nil
+}
+
+// MoveFile moves a file at the top level of Root into the destination subdir, creating it if needed
+func (d *Directory) MoveFile(filename, destination string) error {
+	src, err := utils.BuildSecurePath(d.Root, "", filename)
+	if err != nil {
+		return err
+	}
+	destDir, err := utils.BuildSecurePath(d.Root, "", destination)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.EnsureDirExists(destDir, 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, filepath.Join(destDir, filepath.Base(src))); err != nil {
+		return fmt.Errorf("failed to move file '%s' to '%s': %w", filename, destination, err)
+	}
+	return nil
+}
+
+func filterName(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return []string{n}
+		}
+	}
+	return nil
+}
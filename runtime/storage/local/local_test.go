@@ -0,0 +1,157 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// Compile-time check that Directory implements the Store interface
+var _ storage.Store = (*Directory)(nil)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestDirectory_GetFiles(t *testing.T) {
+	d := NewStore(t.TempDir(), t.TempDir())
+
+	writeFile(t, filepath.Join(d.Root, "parlor.png"), "parlor")
+	writeFile(t, filepath.Join(d.Root, "parlor (1).png"), "parlor")
+	writeFile(t, filepath.Join(d.Root, ".DS_Store"), "hidden")
+	writeFile(t, filepath.Join(d.Root, "some_subfolder", "nested.png"), "nested")
+
+	files, err := d.GetFiles("")
+	if err != nil {
+		t.Fatalf("GetFiles() failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Duplicate content should only be imported once, got: %v", files)
+	}
+
+	remaining, err := d.ListFiles()
+	if err != nil {
+		t.Fatalf("ListFiles() failed: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("Imported files should be archived, got: %v", remaining)
+	}
+	archived, _ := os.ReadDir(filepath.Join(d.Root, archive_dir))
+	if len(archived) != 2 {
+		t.Errorf("Both files should be archived, got %d", len(archived))
+	}
+	if _, err := os.Stat(filepath.Join(d.VaultPath, vault.SCREENSHOT_DIR, files[0])); err != nil {
+		t.Errorf("Imported file should be in the vault: %v", err)
+	}
+}
+
+func TestDirectory_GetFiles_NotFound(t *testing.T) {
+	d := NewStore(t.TempDir(), t.TempDir())
+
+	if _, err := d.GetFiles("missing.png"); err == nil {
+		t.Error("GetFiles() should return error when the file does not exist")
+	}
+	if _, err := d.GetFiles("../escape.png"); err == nil {
+		t.Error("GetFiles() should reject paths outside the root")
+	}
+}
+
+func TestDirectory_Tree(t *testing.T) {
+	d := NewStore(t.TempDir(), t.TempDir())
+
+	tree, err := d.ListTree("vault_sync")
+	if err != nil {
+		t.Fatalf("ListTree() of a missing dir failed: %v", err)
+	}
+	if len(tree) != 0 {
+		t.Errorf("ListTree() of a missing dir should be empty, got: %v", tree)
+	}
+
+	if err := d.WriteFile("vault_sync", "rooms/parlor.md", []byte("# Parlor")); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	if err := d.WriteFile("vault_sync", "people/simon.md", []byte("# Simon")); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+
+	tree, err = d.ListTree("vault_sync")
+	if err != nil {
+		t.Fatalf("ListTree() failed: %v", err)
+	}
+	paths := make([]string, 0, len(tree))
+	for p := range tree {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	if len(paths) != 2 || paths[0] != "people/simon.md" || paths[1] != "rooms/parlor.md" {
+		t.Fatalf("ListTree() should return slash separated paths, got: %v", paths)
+	}
+	if tree["rooms/parlor.md"].MD5 != utils.MD5Bytes([]byte("# Parlor")) {
+		t.Errorf("ListTree() MD5 should be the checksum of the content")
+	}
+
+	data, err := d.ReadFile("vault_sync", "rooms/parlor.md")
+	if err != nil || string(data) != "# Parlor" {
+		t.Errorf("ReadFile() = %q, %v", data, err)
+	}
+
+	if err := d.DeleteFile("vault_sync", "rooms/parlor.md"); err != nil {
+		t.Fatalf("DeleteFile() failed: %v", err)
+	}
+	if err := d.DeleteFile("vault_sync", "rooms/parlor.md"); err != nil {
+		t.Errorf("DeleteFile() of a missing file should not fail: %v", err)
+	}
+	if _, err := d.ReadFile("vault_sync", "rooms/parlor.md"); err == nil {
+		t.Error("ReadFile() should fail for a deleted file")
+	}
+
+	if err := d.WriteFile("vault_sync", "../../escape.md", []byte("x")); err == nil {
+		t.Error("WriteFile() should reject paths outside the dir")
+	}
+}
+
+func TestDirectory_BackupAndRestore(t *testing.T) {
+	d := NewStore(t.TempDir(), t.TempDir())
+	dirs := []string{vault.NOTES_DIR, vault.META_DIR}
+
+	writeFile(t, filepath.Join(d.VaultPath, "notes", "rooms", "parlor.md"), "# Parlor")
+	writeFile(t, filepath.Join(d.VaultPath, "meta", "screenshots.json"), "{}")
+
+	result, err := d.BackupVault(d.VaultPath, dirs)
+	if err != nil {
+		t.Fatalf("BackupVault() failed: %v", err)
+	}
+	if result.Uploaded != 2 {
+		t.Errorf("First backup should copy both files, got %d", result.Uploaded)
+	}
+
+	writeFile(t, filepath.Join(d.VaultPath, "notes", "rooms", "parlor.md"), "# Parlor v2")
+	result, err = d.BackupVault(d.VaultPath, dirs)
+	if err != nil {
+		t.Fatalf("Second BackupVault() failed: %v", err)
+	}
+	if result.Uploaded != 1 || result.Reused != 1 {
+		t.Errorf("Second backup should be incremental, got uploaded=%d reused=%d", result.Uploaded, result.Reused)
+	}
+
+	restored := t.TempDir()
+	if _, err := d.RestoreVault(restored); err != nil {
+		t.Fatalf("RestoreVault() failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(restored, "notes", "rooms", "parlor.md"))
+	if err != nil || string(data) != "# Parlor v2" {
+		t.Errorf("Restored note = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,121 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// ListTree lists every non-hidden file below Root/dir
+func (d *Directory) ListTree(dir string) (map[string]storage.RemoteFile, error) {
+	tree := make(map[string]storage.RemoteFile)
+
+	root, err := d.dirPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return tree, nil
+	}
+
+	files, err := utils.ListFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range files {
+		fullPath := filepath.Join(root, rel)
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read '%s': %w", rel, err)
+		}
+		info, err := os.Stat(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat '%s': %w", rel, err)
+		}
+
+		filePath := filepath.ToSlash(rel)
+		tree[filePath] = storage.RemoteFile{
+			Path:         filePath,
+			MD5:          utils.MD5Bytes(data),
+			ModifiedTime: info.ModTime(),
+		}
+	}
+	return tree, nil
+}
+
+// ReadFile reads the file at filePath inside Root/dir
+func (d *Directory) ReadFile(dir, filePath string) ([]byte, error) {
+	fullPath, err := d.filePath(dir, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", filePath, err)
+	}
+	return data, nil
+}
+
+// WriteFile writes data to filePath inside Root/dir.
+// The content is written to a temp file first so a sync client watching Root never sees a partial file.
+func (d *Directory) WriteFile(dir, filePath string, data []byte) error {
+	fullPath, err := d.filePath(dir, filePath)
+	if err != nil {
+		return err
+	}
+	if err := utils.EnsureDirExists(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(fullPath), "."+filepath.Base(fullPath)+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", filePath, err)
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// DeleteFile removes the file at filePath inside Root/dir. Deleting a missing file is a no-op.
+func (d *Directory) DeleteFile(dir, filePath string) error {
+	fullPath, err := d.filePath(dir, filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// dirPath resolves a slash separated dir relative to Root
+func (d *Directory) dirPath(dir string) (string, error) {
+	if dir == "" {
+		return d.Root, nil
+	}
+	cleanDir, err := utils.ValidatePath(filepath.FromSlash(dir))
+	if err != nil {
+		return "", err
+	}
+	return utils.BuildSecurePath(d.Root, "", cleanDir)
+}
+
+// filePath resolves a slash separated file path inside Root/dir, rejecting paths that escape it
+func (d *Directory) filePath(dir, filePath string) (string, error) {
+	root, err := d.dirPath(dir)
+	if err != nil {
+		return "", err
+	}
+	cleanPath, err := utils.ValidatePath(filepath.FromSlash(filePath))
+	if err != nil {
+		return "", err
+	}
+	return utils.BuildSecurePath(root, "", cleanPath)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// filenameTimePattern matches a capture time embedded in a screenshot file name
+type filenameTimePattern struct {
+	re *regexp.Regexp
+	// layouts are tried in order against the first submatch of re
+	layouts []string
+	// normalize, if set, rewrites the submatch before parsing
+	normalize *strings.Replacer
+}
+
+// filenameTimeSeparators normalizes the clock separators of the generic date time pattern
+var filenameTimeSeparators = strings.NewReplacer("-", ":", "_", ":", ".", ":")
+
+// filenameTimePatterns are the file names produced by common screenshot tools, most specific first
+var filenameTimePatterns = []filenameTimePattern{
+	// Steam: 20250415185723_1.jpg
+	{re: regexp.MustCompile(`^(\d{14})_\d+$`), layouts: []string{"20060102150405"}},
+	// Android: Screenshot_20250415-185723.png, Screenshot_20250415-185723_Game.jpg
+	{re: regexp.MustCompile(`^Screenshot_(\d{8}-\d{6})`), layouts: []string{"20060102-150405"}},
+	// macOS: Screenshot 2025-04-15 at 18.57.23.png, Screen Shot 2020-04-15 at 6.57.23 PM.png
+	{re: regexp.MustCompile(`^Screen ?[Ss]hot (\d{4}-\d{2}-\d{2} at \d{1,2}\.\d{2}\.\d{2}(?: [AP]M)?)`), layouts: []string{"2006-01-02 at 15.04.05", "2006-01-02 at 3.04.05 PM"}},
+	// Windows Snipping Tool: Screenshot 2025-04-15 185723.png
+	{re: regexp.MustCompile(`^Screenshot (\d{4}-\d{2}-\d{2} \d{6})`), layouts: []string{"2006-01-02 150405"}},
+	// Xbox Game Bar: Blue Prince 4_15_2025 6_57_23 PM.png
+	// "_2" is a layout element of its own, so the underscores are swapped out before parsing
+	{re: regexp.MustCompile(`(\d{1,2}_\d{1,2}_\d{4} \d{1,2}_\d{2}_\d{2} [AP]M)$`), layouts: []string{"1:2:2006 3:04:05 PM"}, normalize: strings.NewReplacer("_", ":")},
+}
+
+// genericTimePattern catches date times written out by other tools, e.g. ShareX and OBS: 2025-04-15_18-57-23.png
+var genericTimePattern = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}[ _T]\d{2}[-_.:]\d{2}[-_.:]\d{2})`)
+
+// FilenameCaptureTime parses the capture time out of a screenshot file name, as written by Steam, Windows, macOS and Android.
+// Screenshot tools name files in local time, so that is how the time is read.
+func FilenameCaptureTime(name string) (time.Time, bool) {
+	stem := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	// Newer versions of macOS put a narrow no-break space before AM/PM
+	stem = strings.ReplaceAll(stem, "\u202f", " ")
+
+	for _, pattern := range filenameTimePatterns {
+		match := pattern.re.FindStringSubmatch(stem)
+		if match == nil {
+			continue
+		}
+		value := match[1]
+		if pattern.normalize != nil {
+			value = pattern.normalize.Replace(value)
+		}
+		for _, layout := range pattern.layouts {
+			if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	if match := genericTimePattern.FindStringSubmatch(stem); match != nil {
+		// Only the separators after the date need normalizing: "2025-04-15" keeps its dashes
+		date, clock := match[1][:10], match[1][11:]
+		value := date + " " + filenameTimeSeparators.Replace(clock)
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilenameCaptureTime(t *testing.T) {
+	want := time.Date(2025, 4, 15, 18, 57, 23, 0, time.Local)
+
+	tests := []string{
+		"20250415185723_1.jpg",                       // Steam
+		"Screenshot_20250415-185723.png",             // Android
+		"Screenshot_20250415-185723_Blue Prince.jpg", // Android, with app name
+		"Screenshot 2025-04-15 at 18.57.23.png",      // macOS, 24 hour clock
+		"Screen Shot 2025-04-15 at 6.57.23 PM.png",   // older macOS, 12 hour clock
+		"Screenshot 2025-04-15 at 6.57.23 PM.png",
+		"Screenshot 2025-04-15 185723.png",     // Windows Snipping Tool
+		"Blue Prince 4_15_2025 6_57_23 PM.png", // Xbox Game Bar
+		"BluePrince_2025-04-15_18-57-23.png",   // ShareX
+		"2025-04-15 18-57-23.mkv.png",          // OBS
+		"nested/dir/20250415185723_1.jpg",      // Only the base name matters
+	}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := FilenameCaptureTime(name)
+			if !ok {
+				t.Fatal("FilenameCaptureTime() found no capture time")
+			}
+			if !got.Equal(want) {
+				t.Errorf("FilenameCaptureTime() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFilenameCaptureTime_NoMatch(t *testing.T) {
+	for _, name := range []string{"foyer.png", "IMG_1234.jpg", "2025.png", "Screenshot 2025-13-45 at 99.99.99.png"} {
+		if got, ok := FilenameCaptureTime(name); ok {
+			t.Errorf("FilenameCaptureTime(%q) = %v, expected no match", name, got)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// ThumbnailEdge is the longest side of cached screenshot thumbnails
+	ThumbnailEdge = 256
+	// ThumbnailQuality is the JPEG quality thumbnails are cached at
+	ThumbnailQuality = 80
+
+	// contactSheetPadding is the gap in px around and between contact sheet cells
+	contactSheetPadding = 8
+	// contactSheetLabelGap is the gap in px between a cell's image and its label
+	contactSheetLabelGap = 4
+)
+
+var (
+	contactSheetBackground = color.RGBA{0x20, 0x20, 0x20, 0xff}
+	contactSheetLabelColor = color.White
+	contactSheetFace       = basicfont.Face7x13
+)
+
+// ContactSheetCell is a single image of a contact sheet and the label drawn under it
+type ContactSheetCell struct {
+	Image image.Image
+	Label string
+}
+
+// Thumbnail scales img down so that its longest side is at most maxEdge. Smaller images are returned as is.
+func Thumbnail(img image.Image, maxEdge int) image.Image {
+	bounds := img.Bounds()
+	if max(bounds.Dx(), bounds.Dy()) <= maxEdge {
+		return img
+	}
+	return resizeImage(img, maxEdge, maxEdge)
+}
+
+// EncodeJPEG encodes img as a JPEG at the given quality (1-100)
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	return compressJPEG(img, quality)
+}
+
+// ContactSheet composites cells into a grid with the given number of columns.
+// Each image is scaled to fit within cellEdge px and centered over its label. Rows are only as tall as the
+// tallest scaled image, so a sheet of landscape screenshots doesn't waste space on square cells.
+func ContactSheet(cells []ContactSheetCell, columns, cellEdge int) image.Image {
+	columns = max(1, min(columns, len(cells)))
+	rows := (len(cells) + columns - 1) / columns
+
+	thumbs := make([]image.Image, len(cells))
+	imageHeight := 0
+	for i, cell := range cells {
+		thumbs[i] = Thumbnail(cell.Image, cellEdge)
+		imageHeight = max(imageHeight, thumbs[i].Bounds().Dy())
+	}
+	labelHeight := contactSheetFace.Metrics().Height.Ceil()
+	cellHeight := imageHeight + contactSheetLabelGap + labelHeight
+
+	width := contactSheetPadding + columns*(cellEdge+contactSheetPadding)
+	height := contactSheetPadding + rows*(cellHeight+contactSheetPadding)
+	sheet := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(contactSheetBackground), image.Point{}, draw.Src)
+
+	for i, cell := range cells {
+		x := contactSheetPadding + (i%columns)*(cellEdge+contactSheetPadding)
+		y := contactSheetPadding + (i/columns)*(cellHeight+contactSheetPadding)
+
+		tb := thumbs[i].Bounds()
+		offset := image.Pt(x+(cellEdge-tb.Dx())/2, y+(imageHeight-tb.Dy())/2)
+		draw.Draw(sheet, image.Rectangle{Min: offset, Max: offset.Add(tb.Size())}, thumbs[i], tb.Min, draw.Src)
+
+		drawLabel(sheet, cell.Label, x, y+imageHeight+contactSheetLabelGap, cellEdge)
+	}
+	return sheet
+}
+
+// drawLabel writes label centered in the width px wide box whose top left corner is at x, y.
+// Labels too long for the box keep their start and end, e.g. the date and extension of a screenshot name.
+func drawLabel(dst *image.RGBA, label string, x, y, width int) {
+	advance := font.MeasureString(contactSheetFace, "0").Ceil()
+	maxChars := width / advance
+	if runes := []rune(label); len(runes) > maxChars && maxChars > 3 {
+		head := (maxChars - 3) / 2
+		tail := maxChars - 3 - head
+		label = string(runes[:head]) + "..." + string(runes[len(runes)-tail:])
+	}
+
+	drawer := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(contactSheetLabelColor),
+		Face: contactSheetFace,
+	}
+	textWidth := drawer.MeasureString(label).Ceil()
+	drawer.Dot = fixed.P(x+max(0, (width-textWidth)/2), y+contactSheetFace.Metrics().Ascent.Ceil())
+	drawer.DrawString(label)
+}
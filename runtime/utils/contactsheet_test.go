@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+func solidImage(width, height int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestThumbnail(t *testing.T) {
+	thumb := Thumbnail(solidImage(1920, 1080, color.White), ThumbnailEdge)
+	if b := thumb.Bounds(); b.Dx() != ThumbnailEdge || b.Dy() != 144 {
+		t.Errorf("Thumbnail should keep the aspect ratio within %dpx, got %dx%d", ThumbnailEdge, b.Dx(), b.Dy())
+	}
+
+	small := solidImage(100, 50, color.White)
+	if Thumbnail(small, ThumbnailEdge) != small {
+		t.Error("Images already within the edge should be returned as is")
+	}
+}
+
+func TestContactSheet(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	cells := []ContactSheetCell{
+		{Image: solidImage(400, 200, red), Label: "1: parlor.png"},
+		{Image: solidImage(200, 400, red), Label: "2: a_screenshot_name_that_is_far_too_long_for_its_cell.png"},
+		{Image: solidImage(64, 64, red), Label: "3: foyer.png"},
+	}
+	cellEdge := 128
+	labelHeight := contactSheetFace.Metrics().Height.Ceil()
+
+	sheet := ContactSheet(cells, 2, cellEdge)
+
+	wantWidth := contactSheetPadding + 2*(cellEdge+contactSheetPadding)
+	wantHeight := contactSheetPadding + 2*(cellEdge+contactSheetLabelGap+labelHeight+contactSheetPadding)
+	if b := sheet.Bounds(); b.Dx() != wantWidth || b.Dy() != wantHeight {
+		t.Fatalf("Expected a 2x2 grid of %dx%d, got %dx%d", wantWidth, wantHeight, b.Dx(), b.Dy())
+	}
+
+	// The center of each cell shows its image
+	for i := range cells {
+		x := contactSheetPadding + (i%2)*(cellEdge+contactSheetPadding) + cellEdge/2
+		y := contactSheetPadding + (i/2)*(cellEdge+contactSheetLabelGap+labelHeight+contactSheetPadding) + cellEdge/2
+		if got := color.RGBAModel.Convert(sheet.At(x, y)).(color.RGBA); got != red {
+			t.Errorf("Cell %d should be drawn at its center, got %v", i+1, got)
+		}
+	}
+
+	// Each label is drawn in the strip under its cell
+	labelTop := contactSheetPadding + cellEdge + contactSheetLabelGap
+	labelRect := image.Rect(contactSheetPadding, labelTop, contactSheetPadding+cellEdge, labelTop+labelHeight)
+	if !hasColor(sheet, labelRect, color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Error("Expected a label under the first cell")
+	}
+
+	// Rows of landscape images are only as tall as the images
+	landscape := ContactSheet([]ContactSheetCell{{Image: solidImage(1920, 1080, red), Label: "parlor.png"}}, 4, cellEdge)
+	if got, want := landscape.Bounds().Dy(), 2*contactSheetPadding+72+contactSheetLabelGap+labelHeight; got != want {
+		t.Errorf("Expected a %dpx tall sheet for a single landscape screenshot, got %d", want, got)
+	}
+
+	// The unused fourth cell is left as background
+	emptyRect := image.Rect(wantWidth-contactSheetPadding-cellEdge, wantHeight-contactSheetPadding-cellEdge, wantWidth-contactSheetPadding, wantHeight-contactSheetPadding)
+	if hasColor(sheet, emptyRect, red) {
+		t.Error("Expected the unused cell to be empty")
+	}
+}
+
+func hasColor(img image.Image, rect image.Rectangle, c color.RGBA) bool {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)).(color.RGBA) == c {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// DefaultTileEdge keeps tiles small enough that clients show them at native resolution, so small text stays legible
+	DefaultTileEdge = 768
+	// DefaultTileOverlap is the fraction of a tile shared with its neighbours, so text on a seam appears whole in one tile
+	DefaultTileOverlap = 0.1
+)
+
+// Region is a rectangle given as fractions of an image's width and height, so it applies at any resolution
+type Region struct {
+	X      float64 `json:"x" yaml:"x"`
+	Y      float64 `json:"y" yaml:"y"`
+	Width  float64 `json:"width" yaml:"width"`
+	Height float64 `json:"height" yaml:"height"`
+}
+
+// NamedRegions are the built in regions that can be cropped by name
+var NamedRegions = map[string]Region{
+	"top_left":     {X: 0, Y: 0, Width: 0.5, Height: 0.5},
+	"top_right":    {X: 0.5, Y: 0, Width: 0.5, Height: 0.5},
+	"bottom_left":  {X: 0, Y: 0.5, Width: 0.5, Height: 0.5},
+	"bottom_right": {X: 0.5, Y: 0.5, Width: 0.5, Height: 0.5},
+	"top":          {X: 0, Y: 0, Width: 1, Height: 0.5},
+	"bottom":       {X: 0, Y: 0.5, Width: 1, Height: 0.5},
+	"left":         {X: 0, Y: 0, Width: 0.5, Height: 1},
+	"right":        {X: 0.5, Y: 0, Width: 0.5, Height: 1},
+	"center":       {X: 0.25, Y: 0.25, Width: 0.5, Height: 0.5},
+}
+
+// PresetRegions are the parts of the Blue Prince UI that screenshot tools treat specially.
+// Each can be overridden, and more added, under images.regions in the config.
+var PresetRegions = map[string]Region{
+	// Steps counter in the top left corner
+	"hud_left": {X: 0, Y: 0, Width: 0.22, Height: 0.14},
+	// Key, gem and coin counters in the top right corner
+	"hud_right": {X: 0.78, Y: 0, Width: 0.22, Height: 0.14},
+	// Name of the current room in the bottom right corner
+	"room_label": {X: 0.68, Y: 0.86, Width: 0.32, Height: 0.14},
+}
+
+// HUDRegions are the presets masked out of screenshots by default, so resource counters aren't mistaken for content
+var HUDRegions = []string{"hud_left", "hud_right"}
+
+// RegionNames returns the names of the given regions, sorted
+func RegionNames(regions map[string]Region) []string {
+	names := make([]string, 0, len(regions))
+	for name := range regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that the region lies within the unit square and is not empty
+func (r Region) Validate() error {
+	if r.X < 0 || r.Y < 0 || r.Width <= 0 || r.Height <= 0 || r.X+r.Width > 1 || r.Y+r.Height > 1 {
+		return fmt.Errorf("region %+v must have a positive size and lie within 0-1 of the image", r)
+	}
+	return nil
+}
+
+// Rect converts the region to pixel bounds within bounds
+func (r Region) Rect(bounds image.Rectangle) image.Rectangle {
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	return image.Rect(
+		bounds.Min.X+int(math.Round(r.X*w)),
+		bounds.Min.Y+int(math.Round(r.Y*h)),
+		bounds.Min.X+int(math.Round((r.X+r.Width)*w)),
+		bounds.Min.Y+int(math.Round((r.Y+r.Height)*h)),
+	).Intersect(bounds)
+}
+
+// CropImage copies the part of img inside rect into a new image whose bounds start at (0, 0)
+func CropImage(img image.Image, rect image.Rectangle) (image.Image, error) {
+	rect = rect.Intersect(img.Bounds())
+	if rect.Empty() {
+		return nil, fmt.Errorf("crop %v is outside the %dx%d image", rect, img.Bounds().Dx(), img.Bounds().Dy())
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst, nil
+}
+
+// Tile is one piece of a tiled image
+type Tile struct {
+	Row    int
+	Col    int
+	Bounds image.Rectangle
+}
+
+// TileBounds splits bounds into the smallest grid of tiles that are at most maxEdge pixels on each side,
+// where neighbouring tiles share overlap (0-0.5) of their width or height
+func TileBounds(bounds image.Rectangle, maxEdge int, overlap float64) []Tile {
+	cols, tileW := tileSpan(bounds.Dx(), maxEdge, overlap)
+	rows, tileH := tileSpan(bounds.Dy(), maxEdge, overlap)
+
+	tiles := make([]Tile, 0, rows*cols)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			x := tileOffset(col, cols, bounds.Dx(), tileW)
+			y := tileOffset(row, rows, bounds.Dy(), tileH)
+			tiles = append(tiles, Tile{
+				Row:    row,
+				Col:    col,
+				Bounds: image.Rect(x, y, x+tileW, y+tileH).Add(bounds.Min),
+			})
+		}
+	}
+	return tiles
+}
+
+// tileSpan returns how many tiles cover length and the size of each
+func tileSpan(length, maxEdge int, overlap float64) (int, int) {
+	for n := 1; ; n++ {
+		size := int(math.Ceil(float64(length) / (float64(n)*(1-overlap) + overlap)))
+		if size <= maxEdge || size <= 1 {
+			return n, min(size, length)
+		}
+	}
+}
+
+// tileOffset spreads n tiles of size evenly over length so the first starts at 0 and the last ends at length
+func tileOffset(i, n, length, size int) int {
+	if n == 1 {
+		return 0
+	}
+	return int(math.Round(float64(i) * float64(length-size) / float64(n-1)))
+}
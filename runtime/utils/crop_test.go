@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestRegion_Rect(t *testing.T) {
+	bounds := image.Rect(0, 0, 2560, 1440)
+
+	tests := []struct {
+		name     string
+		region   Region
+		expected image.Rectangle
+	}{
+		{"bottom_right", NamedRegions["bottom_right"], image.Rect(1280, 720, 2560, 1440)},
+		{"center", NamedRegions["center"], image.Rect(640, 360, 1920, 1080)},
+		{"custom", Region{X: 0.1, Y: 0.2, Width: 0.3, Height: 0.4}, image.Rect(256, 288, 1024, 864)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.region.Rect(bounds); got != tt.expected {
+				t.Errorf("Rect() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegion_Validate(t *testing.T) {
+	for _, regions := range []map[string]Region{NamedRegions, PresetRegions} {
+		for _, name := range RegionNames(regions) {
+			if err := regions[name].Validate(); err != nil {
+				t.Errorf("Named region %s should be valid: %v", name, err)
+			}
+		}
+	}
+
+	invalid := []Region{
+		{X: -0.1, Y: 0, Width: 0.5, Height: 0.5},
+		{X: 0.6, Y: 0, Width: 0.5, Height: 0.5},
+		{X: 0, Y: 0, Width: 0, Height: 0.5},
+	}
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("Region %+v should be invalid", r)
+		}
+	}
+}
+
+func TestCropImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	img.Set(60, 70, color.RGBA{255, 0, 0, 255})
+
+	cropped, err := CropImage(img, image.Rect(50, 50, 100, 100))
+	if err != nil {
+		t.Fatalf("CropImage() failed: %v", err)
+	}
+	if cropped.Bounds() != image.Rect(0, 0, 50, 50) {
+		t.Errorf("Cropped bounds = %v, expected 50x50 at the origin", cropped.Bounds())
+	}
+	if r, _, _, _ := cropped.At(10, 20).RGBA(); r>>8 != 255 {
+		t.Error("Cropped pixels should be shifted to the origin")
+	}
+
+	if _, err := CropImage(img, image.Rect(200, 200, 300, 300)); err == nil {
+		t.Error("CropImage() should fail for a rectangle outside the image")
+	}
+}
+
+func TestTileBounds(t *testing.T) {
+	bounds := image.Rect(0, 0, 2560, 1440)
+	tiles := TileBounds(bounds, 768, 0.1)
+
+	if len(tiles) != 4*2 {
+		t.Fatalf("Expected a 4x2 grid, got %d tiles", len(tiles))
+	}
+
+	covered := image.Rectangle{}
+	for _, tile := range tiles {
+		if tile.Bounds.Dx() > 768 || tile.Bounds.Dy() > 768 {
+			t.Errorf("Tile %v is larger than the max edge", tile.Bounds)
+		}
+		if !tile.Bounds.In(bounds) {
+			t.Errorf("Tile %v is outside the image", tile.Bounds)
+		}
+		covered = covered.Union(tile.Bounds)
+	}
+	if covered != bounds {
+		t.Errorf("Tiles should cover the whole image, covered %v", covered)
+	}
+
+	// Neighbouring tiles overlap
+	if !tiles[0].Bounds.Overlaps(tiles[1].Bounds) {
+		t.Errorf("Neighbouring tiles %v and %v should overlap", tiles[0].Bounds, tiles[1].Bounds)
+	}
+
+	// Offsets are kept for cropped areas
+	offset := TileBounds(image.Rect(100, 100, 300, 200), 768, 0.1)
+	if len(offset) != 1 || offset[0].Bounds != image.Rect(100, 100, 300, 200) {
+		t.Errorf("A small area should be a single tile, got %v", offset)
+	}
+}
+
+func TestEncodeToBudget_SmallCrop(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for x := 0; x < 640; x++ {
+		img.Set(x, x%480, color.RGBA{uint8(x), 0, 0, 255})
+	}
+
+	// Crops smaller than minImageEdge are sent as they are
+	cropped, err := CropImage(img, image.Rect(20, 30, 120, 80))
+	if err != nil {
+		t.Fatalf("CropImage() failed: %v", err)
+	}
+	result, err := EncodeToBudget(cropped, 750_000)
+	if err != nil {
+		t.Fatalf("EncodeToBudget() of a 100x50 crop failed: %v", err)
+	}
+	if result.Width != 100 || result.Height != 50 {
+		t.Errorf("EncodeToBudget() = %dx%d, expected the crop's own 100x50", result.Width, result.Height)
+	}
+
+	// A budget too small for any size still fails rather than shrinking forever
+	if _, err := EncodeToBudget(cropped, 10); err == nil {
+		t.Error("EncodeToBudget() should fail for a budget no image fits in")
+	}
+}
@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+)
+
+// EXIF tags read by this package
+const (
+	exifTagOrientation        = 0x0112
+	exifTagDateTime           = 0x0132
+	exifTagExifIFD            = 0x8769
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagOffsetTimeOriginal = 0x9011
+)
+
+// exifTimeLayout is how EXIF stores date times. They carry no zone of their own.
+const exifTimeLayout = "2006:01:02 15:04:05"
+
+// exifTypeSizes is the size in bytes of a single value of each TIFF field type
+var exifTypeSizes = map[uint16]uint32{
+	1:  1, // BYTE
+	2:  1, // ASCII
+	3:  2, // SHORT
+	4:  4, // LONG
+	5:  8, // RATIONAL
+	7:  1, // UNDEFINED
+	9:  4, // SLONG
+	10: 8, // SRATIONAL
+}
+
+var errNoExif = errors.New("no EXIF data found")
+
+// exifField is a single raw TIFF field
+type exifField struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// exifData holds the fields of IFD0 and the Exif sub-IFD of an image
+type exifData struct {
+	order  binary.ByteOrder
+	fields map[uint16]exifField
+}
+
+// readExif finds and parses the EXIF block of a JPEG (APP1 segment), PNG (eXIf chunk), WebP (EXIF chunk) or TIFF file
+func readExif(data []byte) (*exifData, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		return readJPEGExif(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return readPNGExif(data)
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return readWebPExif(data)
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		// A TIFF file is itself the TIFF structure EXIF is stored in
+		return parseTIFF(data)
+	default:
+		return nil, errNoExif
+	}
+}
+
+func readJPEGExif(data []byte) (*exifData, error) {
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("malformed JPEG segment at offset %d", pos)
+		}
+		marker := data[pos+1]
+		// Start of scan: image data follows and there are no more metadata segments
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, fmt.Errorf("malformed JPEG segment at offset %d", pos)
+		}
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return parseTIFF(segment[6:])
+		}
+		pos = end
+	}
+	return nil, errNoExif
+}
+
+func readPNGExif(data []byte) (*exifData, error) {
+	pos := 8
+	for pos+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 8 + length
+		if length < 0 || end+4 > len(data) {
+			return nil, fmt.Errorf("malformed PNG chunk at offset %d", pos)
+		}
+		switch chunkType {
+		case "eXIf":
+			return parseTIFF(data[pos+8 : end])
+		case "IDAT", "IEND":
+			// eXIf must come before the image data
+			return nil, errNoExif
+		}
+		pos = end + 4 // skip the CRC
+	}
+	return nil, errNoExif
+}
+
+func readWebPExif(data []byte) (*exifData, error) {
+	pos := 12
+	for pos+8 <= len(data) {
+		chunkType := string(data[pos : pos+4])
+		length := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		end := pos + 8 + length
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("malformed WebP chunk at offset %d", pos)
+		}
+		if chunkType == "EXIF" {
+			// Some writers keep the JPEG style "Exif" prefix
+			return parseTIFF(bytes.TrimPrefix(data[pos+8:end], []byte("Exif\x00\x00")))
+		}
+		pos = end + length%2 // chunks are padded to an even size
+	}
+	return nil, errNoExif
+}
+
+// parseTIFF reads IFD0 and, if present, the Exif sub-IFD of a TIFF structured block
+func parseTIFF(tiff []byte) (*exifData, error) {
+	if len(tiff) < 8 {
+		return nil, errors.New("EXIF block too short")
+	}
+
+	exif := &exifData{fields: make(map[uint16]exifField)}
+	switch string(tiff[:2]) {
+	case "II":
+		exif.order = binary.LittleEndian
+	case "MM":
+		exif.order = binary.BigEndian
+	default:
+		return nil, errors.New("invalid EXIF byte order")
+	}
+	if exif.order.Uint16(tiff[2:4]) != 42 {
+		return nil, errors.New("invalid EXIF header")
+	}
+
+	if err := exif.readIFD(tiff, exif.order.Uint32(tiff[4:8])); err != nil {
+		return nil, err
+	}
+	if offset, ok := exif.uint(exifTagExifIFD); ok {
+		if err := exif.readIFD(tiff, offset); err != nil {
+			return nil, err
+		}
+	}
+	return exif, nil
+}
+
+// readIFD adds every field of the IFD at offset to e.fields. Fields of unknown types are skipped.
+func (e *exifData) readIFD(tiff []byte, offset uint32) error {
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return errors.New("EXIF IFD offset out of range")
+	}
+	count := int(e.order.Uint16(tiff[offset : offset+2]))
+	start := int(offset) + 2
+	if start+count*12 > len(tiff) {
+		return errors.New("EXIF IFD truncated")
+	}
+
+	for i := 0; i < count; i++ {
+		entry := tiff[start+i*12 : start+(i+1)*12]
+		tag := e.order.Uint16(entry[0:2])
+		typ := e.order.Uint16(entry[2:4])
+		n := e.order.Uint32(entry[4:8])
+
+		size, ok := exifTypeSizes[typ]
+		if !ok {
+			continue
+		}
+		total := uint64(size) * uint64(n)
+		var value []byte
+		if total <= 4 {
+			value = entry[8 : 8+total]
+		} else {
+			valueOffset := uint64(e.order.Uint32(entry[8:12]))
+			if valueOffset+total > uint64(len(tiff)) {
+				continue
+			}
+			value = tiff[valueOffset : valueOffset+total]
+		}
+		e.fields[tag] = exifField{typ: typ, count: n, value: value}
+	}
+	return nil
+}
+
+// str returns an ASCII field without its NUL terminator
+func (e *exifData) str(tag uint16) (string, bool) {
+	field, ok := e.fields[tag]
+	if !ok || field.typ != 2 {
+		return "", false
+	}
+	return strings.TrimRight(string(field.value), "\x00 "), true
+}
+
+// uint returns the first value of a SHORT or LONG field
+func (e *exifData) uint(tag uint16) (uint32, bool) {
+	field, ok := e.fields[tag]
+	if !ok || field.count == 0 {
+		return 0, false
+	}
+	switch field.typ {
+	case 3:
+		return uint32(e.order.Uint16(field.value)), true
+	case 4:
+		return e.order.Uint32(field.value), true
+	}
+	return 0, false
+}
+
+// ExifCaptureTime returns when an image was taken according to its EXIF DateTimeOriginal, falling back to DateTime.
+// EXIF times are read as local time unless the image records its UTC offset.
+func ExifCaptureTime(data []byte) (time.Time, bool) {
+	exif, err := readExif(data)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	value, ok := exif.str(exifTagDateTimeOriginal)
+	if !ok {
+		if value, ok = exif.str(exifTagDateTime); !ok {
+			return time.Time{}, false
+		}
+	}
+
+	loc := time.Local
+	if offset, ok := exif.str(exifTagOffsetTimeOriginal); ok {
+		if t, err := time.Parse("-07:00", offset); err == nil {
+			_, secs := t.Zone()
+			loc = time.FixedZone(offset, secs)
+		}
+	}
+
+	t, err := time.ParseInLocation(exifTimeLayout, value, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// ExifOrientation returns the EXIF orientation (1-8) of an image, or 1 (upright) if it has none
+func ExifOrientation(data []byte) int {
+	exif, err := readExif(data)
+	if err != nil {
+		return 1
+	}
+	orientation, ok := exif.uint(exifTagOrientation)
+	if !ok || orientation < 1 || orientation > 8 {
+		return 1
+	}
+	return int(orientation)
+}
+
+// ApplyOrientation returns img transformed so that an image stored with the given EXIF orientation displays upright
+func ApplyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	src := img.Bounds()
+	w, h := src.Dx(), src.Dy()
+	// Orientations 5-8 swap width and height
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotated 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // mirrored along the top left to bottom right diagonal
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise to display
+				dx, dy = h-1-y, x
+			case 7: // mirrored along the top right to bottom left diagonal
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90 counter clockwise to display
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(src.Min.X+x, src.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+)
+
+// testExifField is a field written by buildTIFF
+type testExifField struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+func asciiField(tag uint16, value string) testExifField {
+	return testExifField{tag: tag, typ: 2, count: uint32(len(value) + 1), value: append([]byte(value), 0)}
+}
+
+// buildTIFF lays out IFD0 and, if exifIFD is not empty, an Exif sub-IFD pointed to from IFD0
+func buildTIFF(order binary.AppendByteOrder, ifd0, exifIFD []testExifField) []byte {
+	if len(exifIFD) > 0 {
+		ifd0 = append(ifd0, testExifField{tag: exifTagExifIFD, typ: 4, count: 1, value: make([]byte, 4)})
+	}
+	ifdSize := func(fields []testExifField) int { return 2 + len(fields)*12 + 4 }
+	ifd0Offset := 8
+	exifOffset := ifd0Offset + ifdSize(ifd0)
+	dataOffset := exifOffset
+	if len(exifIFD) > 0 {
+		dataOffset += ifdSize(exifIFD)
+	}
+
+	var data []byte
+	writeIFD := func(buf []byte, fields []testExifField) []byte {
+		buf = order.AppendUint16(buf, uint16(len(fields)))
+		for _, f := range fields {
+			value := f.value
+			if f.tag == exifTagExifIFD {
+				value = order.AppendUint32(nil, uint32(exifOffset))
+			}
+			buf = order.AppendUint16(buf, f.tag)
+			buf = order.AppendUint16(buf, f.typ)
+			buf = order.AppendUint32(buf, f.count)
+			if len(value) <= 4 {
+				buf = append(buf, append(value, make([]byte, 4-len(value))...)...)
+			} else {
+				buf = order.AppendUint32(buf, uint32(dataOffset+len(data)))
+				data = append(data, value...)
+			}
+		}
+		return order.AppendUint32(buf, 0)
+	}
+
+	tiff := []byte("MM")
+	if order.String() == binary.LittleEndian.String() {
+		tiff = []byte("II")
+	}
+	tiff = order.AppendUint16(tiff, 42)
+	tiff = order.AppendUint32(tiff, uint32(ifd0Offset))
+	tiff = writeIFD(tiff, ifd0)
+	if len(exifIFD) > 0 {
+		tiff = writeIFD(tiff, exifIFD)
+	}
+	return append(tiff, data...)
+}
+
+// jpegWithExif encodes a small JPEG and inserts tiff as its EXIF APP1 segment
+func jpegWithExif(t *testing.T, tiff []byte) []byte {
+	t.Helper()
+	return encodeJPEGWithExif(t, image.NewRGBA(image.Rect(0, 0, 8, 8)), tiff)
+}
+
+// encodeJPEGWithExif encodes img as a JPEG and inserts tiff as its EXIF APP1 segment
+func encodeJPEGWithExif(t *testing.T, img image.Image, tiff []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("Failed to encode jpeg: %v", err)
+	}
+	encoded := buf.Bytes()
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, encoded[:2]...)
+	out = append(out, segment...)
+	return append(out, encoded[2:]...)
+}
+
+// pngWithExif encodes a small PNG and inserts tiff as an eXIf chunk right after IHDR
+func pngWithExif(t *testing.T, tiff []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatalf("Failed to encode png: %v", err)
+	}
+	encoded := buf.Bytes()
+	// Signature (8) + IHDR chunk (4 length + 4 type + 13 data + 4 CRC)
+	ihdrEnd := 8 + 25
+
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(tiff)))
+	chunk = append(chunk, "eXIf"...)
+	chunk = append(chunk, tiff...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	out := append([]byte{}, encoded[:ihdrEnd]...)
+	out = append(out, chunk...)
+	return append(out, encoded[ihdrEnd:]...)
+}
+
+func TestExifCaptureTime(t *testing.T) {
+	original := []testExifField{asciiField(exifTagDateTimeOriginal, "2025:04:15 18:57:23")}
+
+	tests := []struct {
+		name string
+		data []byte
+		want time.Time
+	}{
+		{
+			name: "jpeg big endian",
+			data: jpegWithExif(t, buildTIFF(binary.BigEndian, nil, original)),
+			want: time.Date(2025, 4, 15, 18, 57, 23, 0, time.Local),
+		},
+		{
+			name: "jpeg little endian",
+			data: jpegWithExif(t, buildTIFF(binary.LittleEndian, nil, original)),
+			want: time.Date(2025, 4, 15, 18, 57, 23, 0, time.Local),
+		},
+		{
+			name: "png",
+			data: pngWithExif(t, buildTIFF(binary.LittleEndian, nil, original)),
+			want: time.Date(2025, 4, 15, 18, 57, 23, 0, time.Local),
+		},
+		{
+			name: "prefers DateTimeOriginal over DateTime",
+			data: jpegWithExif(t, buildTIFF(binary.BigEndian, []testExifField{asciiField(exifTagDateTime, "2026:01:01 00:00:00")}, original)),
+			want: time.Date(2025, 4, 15, 18, 57, 23, 0, time.Local),
+		},
+		{
+			name: "falls back to DateTime",
+			data: jpegWithExif(t, buildTIFF(binary.BigEndian, []testExifField{asciiField(exifTagDateTime, "2026:01:01 08:30:00")}, nil)),
+			want: time.Date(2026, 1, 1, 8, 30, 0, 0, time.Local),
+		},
+		{
+			name: "honors OffsetTimeOriginal",
+			data: jpegWithExif(t, buildTIFF(binary.BigEndian, nil, append(original, asciiField(exifTagOffsetTimeOriginal, "+09:00")))),
+			want: time.Date(2025, 4, 15, 9, 57, 23, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExifCaptureTime(tt.data)
+			if !ok {
+				t.Fatal("ExifCaptureTime() found no capture time")
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ExifCaptureTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExifCaptureTime_Missing(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatalf("Failed to encode jpeg: %v", err)
+	}
+
+	inputs := map[string][]byte{
+		"jpeg without exif": buf.Bytes(),
+		"not an image":      []byte("hello"),
+		"truncated exif":    jpegWithExif(t, []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x05")),
+	}
+	for name, data := range inputs {
+		if _, ok := ExifCaptureTime(data); ok {
+			t.Errorf("%s: expected no capture time", name)
+		}
+	}
+}
@@ -149,7 +149,7 @@ func ShouldSkipPath(path string, info os.DirEntry) bool {
 // EnsureDirExists checks if a directory exists at the given path, and creates it if it doesn't.
 // It uses the provided file mode for creation.
 func EnsureDirExists(path string, perm os.FileMode) error {
-	_, err := os.Stat(path)
+	info, err := os.Stat(path)
 	// create dir if does not exist
 	if os.IsNotExist(err) {
 		// rerport errors during creation
@@ -163,10 +163,18 @@ func EnsureDirExists(path string, perm os.FileMode) error {
 	if err != nil {
 		return fmt.Errorf("failed to check directory '%s': %w", path, err)
 	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("path '%s' exists but is not a directory", path)
+	}
 	return nil
 }
 
 func GetMimeType(path string) string {
+	if mimeType, ok := imageMimeTypes[strings.ToLower(filepath.Ext(path))]; ok {
+		return mimeType
+	}
+
 	// Basic MIME type detection by extension
 	mimeType := mime.TypeByExtension(filepath.Ext(path))
 	if mimeType == "" {
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"strings"
+
+	// Register every decoder image.Decode should know about. jpeg and png are already imported by img.go.
+	_ "image/gif"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// imageMimeTypes maps the extensions of the image formats that can be decoded to their MIME types.
+// They are spelled out because mime.TypeByExtension depends on the host's MIME database, which often lacks webp.
+var imageMimeTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".bmp":  "image/bmp",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+}
+
+// DecodeImage decodes any registered image format and rotates the result upright according to its EXIF orientation.
+// Returns the image and its format name as reported by image.Decode.
+func DecodeImage(data []byte) (image.Image, string, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	return ApplyOrientation(img, ExifOrientation(data)), format, nil
+}
+
+// NormalizedFormat is the format an image is re-encoded in: "png" for lossless sources and "jpeg" for everything else.
+// Clients only display JPEG and PNG, so other formats are never sent as is.
+func NormalizedFormat(format string) string {
+	switch strings.ToLower(format) {
+	case "png", "gif", "bmp", "tiff":
+		return "png"
+	default:
+		return "jpeg"
+	}
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/tiff"
+)
+
+// tinyWebP is a 1x1 lossless WebP. The x/image module can only decode WebP, so it is checked in rather than encoded.
+const tinyWebP = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+func TestDecodeImage_Formats(t *testing.T) {
+	src := solidImage(6, 4, color.RGBA{0x20, 0x40, 0x60, 0xff})
+
+	encode := func(fn func(*bytes.Buffer) error) []byte {
+		var buf bytes.Buffer
+		if err := fn(&buf); err != nil {
+			t.Fatalf("Failed to encode test image: %v", err)
+		}
+		return buf.Bytes()
+	}
+	webp, err := base64.StdEncoding.DecodeString(tinyWebP)
+	if err != nil {
+		t.Fatalf("Failed to decode webp fixture: %v", err)
+	}
+
+	tests := []struct {
+		format     string
+		data       []byte
+		normalized string
+	}{
+		{"gif", encode(func(b *bytes.Buffer) error { return gif.Encode(b, src, nil) }), "png"},
+		{"bmp", encode(func(b *bytes.Buffer) error { return bmp.Encode(b, src) }), "png"},
+		{"tiff", encode(func(b *bytes.Buffer) error { return tiff.Encode(b, src, nil) }), "png"},
+		{"webp", webp, "jpeg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			img, format, err := DecodeImage(tt.data)
+			if err != nil {
+				t.Fatalf("DecodeImage() failed: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("Expected format %s, got %s", tt.format, format)
+			}
+			if img.Bounds().Empty() {
+				t.Error("Decoded image should not be empty")
+			}
+			if got := NormalizedFormat(format); got != tt.normalized {
+				t.Errorf("NormalizedFormat(%s) = %s, want %s", format, got, tt.normalized)
+			}
+		})
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// A 3x2 image where every pixel is distinct:
+	//   0 1 2
+	//   3 4 5
+	src := image.NewGray(image.Rect(0, 0, 3, 2))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i)
+	}
+
+	tests := []struct {
+		orientation int
+		want        [][]uint8 // rows of the upright image
+	}{
+		{1, [][]uint8{{0, 1, 2}, {3, 4, 5}}},
+		{2, [][]uint8{{2, 1, 0}, {5, 4, 3}}},
+		{3, [][]uint8{{5, 4, 3}, {2, 1, 0}}},
+		{4, [][]uint8{{3, 4, 5}, {0, 1, 2}}},
+		{5, [][]uint8{{0, 3}, {1, 4}, {2, 5}}},
+		{6, [][]uint8{{3, 0}, {4, 1}, {5, 2}}},
+		{7, [][]uint8{{5, 2}, {4, 1}, {3, 0}}},
+		{8, [][]uint8{{2, 5}, {1, 4}, {0, 3}}},
+	}
+
+	for _, tt := range tests {
+		got := ApplyOrientation(src, tt.orientation)
+		if got.Bounds().Dx() != len(tt.want[0]) || got.Bounds().Dy() != len(tt.want) {
+			t.Errorf("Orientation %d: expected %dx%d, got %v", tt.orientation, len(tt.want[0]), len(tt.want), got.Bounds())
+			continue
+		}
+		for y, row := range tt.want {
+			for x, want := range row {
+				if v := color.GrayModel.Convert(got.At(x, y)).(color.Gray).Y; v != want {
+					t.Errorf("Orientation %d: pixel (%d,%d) = %d, want %d", tt.orientation, x, y, v, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeImage_ExifOrientation(t *testing.T) {
+	// Stored sideways: red on the left, blue on the right. Orientation 6 turns it 90 degrees clockwise to display.
+	red, blue := color.RGBA{0xff, 0, 0, 0xff}, color.RGBA{0, 0, 0xff, 0xff}
+	stored := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	draw.Draw(stored, image.Rect(0, 0, 16, 16), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(stored, image.Rect(16, 0, 32, 16), image.NewUniform(blue), image.Point{}, draw.Src)
+
+	orientation := testExifField{tag: exifTagOrientation, typ: 3, count: 1, value: binary.BigEndian.AppendUint16(nil, 6)}
+	data := encodeJPEGWithExif(t, stored, buildTIFF(binary.BigEndian, []testExifField{orientation}, nil))
+
+	if got := ExifOrientation(data); got != 6 {
+		t.Fatalf("ExifOrientation() = %d, want 6", got)
+	}
+	img, _, err := DecodeImage(data)
+	if err != nil {
+		t.Fatalf("DecodeImage() failed: %v", err)
+	}
+	if img.Bounds().Dx() != 16 || img.Bounds().Dy() != 32 {
+		t.Fatalf("Expected the upright image to be 16x32, got %v", img.Bounds())
+	}
+
+	// JPEG is lossy, so compare the dominant channel
+	top := color.RGBAModel.Convert(img.At(8, 4)).(color.RGBA)
+	bottom := color.RGBAModel.Convert(img.At(8, 28)).(color.RGBA)
+	if top.R < top.B || bottom.B < bottom.R {
+		t.Errorf("Expected red on top and blue at the bottom, got %v and %v", top, bottom)
+	}
+}
+
+func TestGetMimeType_Images(t *testing.T) {
+	tests := map[string]string{
+		"parlor.jpg":  "image/jpeg",
+		"parlor.JPEG": "image/jpeg",
+		"parlor.png":  "image/png",
+		"parlor.gif":  "image/gif",
+		"parlor.webp": "image/webp",
+		"parlor.bmp":  "image/bmp",
+		"parlor.tif":  "image/tiff",
+		"parlor.tiff": "image/tiff",
+		"notes.md":    "text/markdown; charset=utf-8",
+	}
+	for name, want := range tests {
+		if got := GetMimeType(name); got != want {
+			t.Errorf("GetMimeType(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
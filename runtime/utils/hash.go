@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	dHashWidth  = 9
+	dHashHeight = 8
+)
+
+// HashBytes returns the hex encoded SHA-256 digest of data
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// MD5Bytes returns the hex encoded MD5 digest of data.
+// Only use it to compare against checksums reported by storage backends, not for deduplication.
+func MD5Bytes(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// DHash computes a 64-bit difference hash of an image.
+// The image is shrunk to 9x8 grayscale and each bit records whether a pixel is brighter than its right neighbour,
+// so re-encoded, resized or slightly shifted copies of the same scene produce hashes a few bits apart.
+func DHash(img image.Image) uint64 {
+	gray := image.NewGray(image.Rect(0, 0, dHashWidth, dHashHeight))
+	draw.BiLinear.Scale(gray, gray.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < dHashHeight; y++ {
+		for x := 0; x < dHashWidth-1; x++ {
+			hash <<= 1
+			if gray.GrayAt(x, y).Y > gray.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// FormatDHash encodes a difference hash as a fixed-width hex string suitable for JSON
+func FormatDHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// ParseDHash decodes a hex string produced by FormatDHash
+func ParseDHash(s string) (uint64, error) {
+	hash, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid dhash '%s': %w", s, err)
+	}
+	return hash, nil
+}
+
+// HammingDistance returns the number of differing bits between two hashes
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// PerceptualHash decodes encoded image data and returns its formatted difference hash
+func PerceptualHash(data []byte) (string, error) {
+	img, _, err := DecodeImage(data)
+	if err != nil {
+		return "", err
+	}
+	return FormatDHash(DHash(img)), nil
+}
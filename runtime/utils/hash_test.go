@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// gradientImage builds a deterministic test image with a diagonal gradient and a bright square
+func gradientImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8((x + y) * 255 / (width + height))
+			if x > width/4 && x < width/2 && y > height/4 && y < height/2 {
+				v = 255
+			}
+			img.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+func TestHashBytes(t *testing.T) {
+	// SHA-256 of the empty string
+	expected := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if got := HashBytes(nil); got != expected {
+		t.Errorf("HashBytes(nil) = %s, expected %s", got, expected)
+	}
+
+	if HashBytes([]byte("a")) == HashBytes([]byte("b")) {
+		t.Error("HashBytes() should differ for different content")
+	}
+}
+
+func TestMD5Bytes(t *testing.T) {
+	// MD5 of the empty string
+	expected := "d41d8cd98f00b204e9800998ecf8427e"
+	if got := MD5Bytes(nil); got != expected {
+		t.Errorf("MD5Bytes(nil) = %s, expected %s", got, expected)
+	}
+}
+
+func TestDHash_SimilarImages(t *testing.T) {
+	original := gradientImage(320, 180)
+
+	// Re-encoding as a lossy JPEG at a different size should barely move the hash
+	small := gradientImage(160, 90)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, small, &jpeg.Options{Quality: 60}); err != nil {
+		t.Fatalf("Failed to encode jpeg: %v", err)
+	}
+	reencoded, _, err := image.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode jpeg: %v", err)
+	}
+
+	distance := HammingDistance(DHash(original), DHash(reencoded))
+	if distance > 10 {
+		t.Errorf("Expected resized re-encoded image to be within 10 bits, got %d", distance)
+	}
+}
+
+func TestDHash_DifferentImages(t *testing.T) {
+	original := gradientImage(320, 180)
+
+	// Mirror the image horizontally so every left/right comparison flips
+	mirrored := image.NewRGBA(original.Bounds())
+	for y := 0; y < 180; y++ {
+		for x := 0; x < 320; x++ {
+			mirrored.Set(319-x, y, original.At(x, y))
+		}
+	}
+
+	distance := HammingDistance(DHash(original), DHash(mirrored))
+	if distance <= 10 {
+		t.Errorf("Expected mirrored image to be more than 10 bits away, got %d", distance)
+	}
+}
+
+func TestFormatAndParseDHash(t *testing.T) {
+	testCases := []uint64{0, 1, 0xdeadbeef, 0xffffffffffffffff}
+	for _, hash := range testCases {
+		formatted := FormatDHash(hash)
+		if len(formatted) != 16 {
+			t.Errorf("FormatDHash(%d) should be 16 chars, got %q", hash, formatted)
+		}
+		parsed, err := ParseDHash(formatted)
+		if err != nil {
+			t.Errorf("ParseDHash(%q) failed: %v", formatted, err)
+		}
+		if parsed != hash {
+			t.Errorf("ParseDHash(FormatDHash(%d)) = %d", hash, parsed)
+		}
+	}
+
+	if _, err := ParseDHash("not-a-hash"); err == nil {
+		t.Error("ParseDHash() should fail for invalid input")
+	}
+}
+
+func TestPerceptualHash(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, gradientImage(64, 64)); err != nil {
+		t.Fatalf("Failed to encode png: %v", err)
+	}
+
+	hash, err := PerceptualHash(buf.Bytes())
+	if err != nil {
+		t.Fatalf("PerceptualHash() failed: %v", err)
+	}
+	if len(hash) != 16 {
+		t.Errorf("PerceptualHash() should return a 16 char hex string, got %q", hash)
+	}
+
+	if _, err := PerceptualHash([]byte("not an image")); err == nil {
+		t.Error("PerceptualHash() should fail for non-image data")
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	testCases := []struct {
+		a, b     uint64
+		expected int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xff, 0x0f, 4},
+		{0, 0xffffffffffffffff, 64},
+	}
+
+	for _, tc := range testCases {
+		if got := HammingDistance(tc.a, tc.b); got != tc.expected {
+			t.Errorf("HammingDistance(%x, %x) = %d, expected %d", tc.a, tc.b, got, tc.expected)
+		}
+	}
+}
@@ -13,10 +13,23 @@ import (
 	"golang.org/x/image/draw"
 )
 
+const (
+	// MaxImageEdge caps the longest side of images sent to a client. Vision models downscale anything larger anyway.
+	MaxImageEdge = 1568
+	// minImageEdge is the smallest longest side CompressImageToBudget will shrink an image to before giving up
+	minImageEdge = 128
+	// resizeStep is how much each side shrinks between CompressImageToBudget attempts
+	resizeStep = 0.75
+)
+
+// budgetQualities are the JPEG qualities tried at each resolution, best first
+var budgetQualities = []int{85, 70, 55, 40}
+
 // CompressedImageResult holds the compressed image data and metadata
 type CompressedImageResult struct {
 	Data             string  `json:"data"`            // base64 encoded compressed image
 	Format           string  `json:"format"`          // jpeg, png, etc.
+	MimeType         string  `json:"mime_type"`       // MIME type matching Format
 	OriginalSize     int64   `json:"original_size"`   // original file size in bytes
 	CompressedSize   int     `json:"compressed_size"` // compressed size in bytes
 	Width            int     `json:"width"`
@@ -31,57 +44,148 @@ func CompressImage(filepath string, maxWidth, maxHeight int, quality int) (*Comp
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
-	originalSize := int64(len(originalData))
 
 	// Decode the image
-	img, format, err := image.Decode(bytes.NewReader(originalData))
+	img, format, err := DecodeImage(originalData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode image: %w", err)
+		return nil, err
 	}
 
-	// Get original dimensions
-	bounds := img.Bounds()
-	originalWidth := bounds.Dx()
-	originalHeight := bounds.Dy()
-
 	// Resize if needed
 	resizedImg := img
-	if originalWidth > maxWidth || originalHeight > maxHeight {
+	if img.Bounds().Dx() > maxWidth || img.Bounds().Dy() > maxHeight {
 		resizedImg = resizeImage(img, maxWidth, maxHeight)
 	}
 
 	// Compress based on format
 	var compressedData []byte
-	switch strings.ToLower(format) {
-	case "jpeg", "jpg":
-		compressedData, err = compressJPEG(resizedImg, quality)
+	format = NormalizedFormat(format)
+	switch format {
 	case "png":
 		compressedData, err = compressPNG(resizedImg)
 	default:
-		// Convert to JPEG for unsupported formats
 		compressedData, err = compressJPEG(resizedImg, quality)
-		format = "jpeg"
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to compress image: %w", err)
 	}
 
-	// Calculate compression ratio
-	compressionRatio := float64(originalSize) / float64(len(compressedData))
+	return newCompressedImageResult(compressedData, format, resizedImg, int64(len(originalData))), nil
+}
+
+// CompressImageToBudget loads an image file and encodes it so that its base64 encoding is at most maxBytes.
+// Upright JPEG and PNG images that already fit and are no larger than MaxImageEdge are returned unchanged.
+// Otherwise the image is re-encoded with EncodeToBudget.
+func CompressImageToBudget(filepath string, maxBytes int) (*CompressedImageResult, error) {
+	img, format, originalData, err := LoadImage(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if base64.StdEncoding.EncodedLen(len(originalData)) <= maxBytes && max(width, height) <= MaxImageEdge &&
+		ImageMimeType(format) != "" && ExifOrientation(originalData) == 1 {
+		return newCompressedImageResult(originalData, format, img, int64(len(originalData))), nil
+	}
+
+	result, err := EncodeToBudget(img, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	result.OriginalSize = int64(len(originalData))
+	result.CompressionRatio = float64(result.OriginalSize) / float64(result.CompressedSize)
+	return result, nil
+}
 
-	// Encode to base64
-	base64Data := base64.StdEncoding.EncodeToString(compressedData)
+// EncodeToBudget encodes img as JPEG so that its base64 encoding is at most maxBytes.
+// It starts at MaxImageEdge, or the image's own size if smaller, and lowers quality first, then resolution, until the image fits.
+func EncodeToBudget(img image.Image, maxBytes int) (*CompressedImageResult, error) {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+
+	// Start from the largest size a client will actually use
+	scale := min(1.0, float64(MaxImageEdge)/float64(max(width, height)))
+	for {
+		w, h := int(float64(width)*scale), int(float64(height)*scale)
+		// Small images are always tried at full size; only downscaling stops at minImageEdge
+		if scale < 1 && max(w, h) < minImageEdge {
+			return nil, fmt.Errorf("image cannot be compressed to fit in %d bytes", maxBytes)
+		}
+
+		resized := img
+		if scale < 1 {
+			resized = resizeImage(img, w, h)
+		}
+		for _, quality := range budgetQualities {
+			data, err := compressJPEG(resized, quality)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compress image: %w", err)
+			}
+			if base64.StdEncoding.EncodedLen(len(data)) <= maxBytes {
+				return newCompressedImageResult(data, "jpeg", resized, int64(len(data))), nil
+			}
+		}
+		scale *= resizeStep
+	}
+}
 
+// LoadImage reads and decodes an image file, returning the upright image, its format name and the raw file content
+func LoadImage(filepath string) (image.Image, string, []byte, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	img, format, err := DecodeImage(data)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return img, format, data, nil
+}
+
+// MaskRegions returns a copy of img with each region filled in black
+func MaskRegions(img image.Image, regions []Region) image.Image {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+
+	for _, region := range regions {
+		draw.Draw(dst, region.Rect(dst.Bounds()), image.Black, image.Point{}, draw.Src)
+	}
+	return dst
+}
+
+// ExtractRegion returns only the part of img inside region
+func ExtractRegion(img image.Image, region Region) (image.Image, error) {
+	if err := region.Validate(); err != nil {
+		return nil, err
+	}
+	return CropImage(img, region.Rect(img.Bounds()))
+}
+
+// ImageMimeType returns the MIME type for an image format name as reported by image.Decode,
+// or "" if the format isn't one clients can display
+func ImageMimeType(format string) string {
+	switch strings.ToLower(format) {
+	case "jpeg", "jpg":
+		return "image/jpeg"
+	case "png":
+		return "image/png"
+	}
+	return ""
+}
+
+func newCompressedImageResult(data []byte, format string, img image.Image, originalSize int64) *CompressedImageResult {
 	return &CompressedImageResult{
-		Data:             base64Data,
+		Data:             base64.StdEncoding.EncodeToString(data),
 		Format:           format,
+		MimeType:         ImageMimeType(format),
 		OriginalSize:     originalSize,
-		CompressedSize:   len(compressedData),
-		Width:            resizedImg.Bounds().Dx(),
-		Height:           resizedImg.Bounds().Dy(),
-		CompressionRatio: compressionRatio,
-	}, nil
+		CompressedSize:   len(data),
+		Width:            img.Bounds().Dx(),
+		Height:           img.Bounds().Dy(),
+		CompressionRatio: float64(originalSize) / float64(len(data)),
+	}
 }
 
 // resizeImage resizes an image while maintaining aspect ratio
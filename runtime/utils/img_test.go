@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeNoisyPNG writes a PNG that compresses badly, so budgets are hard to meet
+func writeNoisyPNG(t *testing.T, width, height int) string {
+	t.Helper()
+
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(rng.Intn(256)), uint8(x), uint8(y), 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode png: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "noisy.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write png: %v", err)
+	}
+	return path
+}
+
+func TestCompressImageToBudget_AlreadyFits(t *testing.T) {
+	path := writeNoisyPNG(t, 64, 48)
+
+	result, err := CompressImageToBudget(path, 1<<20)
+	if err != nil {
+		t.Fatalf("CompressImageToBudget() failed: %v", err)
+	}
+	if result.Format != "png" || result.MimeType != "image/png" {
+		t.Errorf("Small images should keep their format, got %s (%s)", result.Format, result.MimeType)
+	}
+	if result.Width != 64 || result.Height != 48 {
+		t.Errorf("Small images should keep their size, got %dx%d", result.Width, result.Height)
+	}
+}
+
+func TestCompressImageToBudget_Shrinks(t *testing.T) {
+	path := writeNoisyPNG(t, 2000, 1000)
+	budget := 40_000
+
+	result, err := CompressImageToBudget(path, budget)
+	if err != nil {
+		t.Fatalf("CompressImageToBudget() failed: %v", err)
+	}
+	if len(result.Data) > budget {
+		t.Errorf("Encoded image is %d bytes, expected at most %d", len(result.Data), budget)
+	}
+	if result.MimeType != "image/jpeg" {
+		t.Errorf("Re-encoded images should be JPEG, got %s", result.MimeType)
+	}
+	if result.Width > MaxImageEdge || result.Height > MaxImageEdge {
+		t.Errorf("Image should be at most %d px on its longest side, got %dx%d", MaxImageEdge, result.Width, result.Height)
+	}
+	// Aspect ratio is kept
+	if ratio := float64(result.Width) / float64(result.Height); ratio < 1.9 || ratio > 2.1 {
+		t.Errorf("Aspect ratio should be kept, got %dx%d", result.Width, result.Height)
+	}
+}
+
+func TestCompressImageToBudget_Impossible(t *testing.T) {
+	path := writeNoisyPNG(t, 400, 400)
+
+	if _, err := CompressImageToBudget(path, 100); err == nil {
+		t.Error("CompressImageToBudget() should fail when no size fits the budget")
+	}
+}
+
+func TestImageMimeType(t *testing.T) {
+	tests := map[string]string{
+		"jpeg": "image/jpeg",
+		"png":  "image/png",
+		"bmp":  "",
+	}
+	for format, expected := range tests {
+		if got := ImageMimeType(format); got != expected {
+			t.Errorf("ImageMimeType(%q) = %q, expected %q", format, got, expected)
+		}
+	}
+}
+
+func TestMaskRegions(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	masked := MaskRegions(img, []Region{PresetRegions["hud_left"]})
+
+	if r, _, _, _ := masked.At(5, 5).RGBA(); r != 0 {
+		t.Error("Pixels inside the masked region should be black")
+	}
+	if r, _, _, _ := masked.At(50, 50).RGBA(); r>>8 != 255 {
+		t.Error("Pixels outside the masked region should be kept")
+	}
+	if r, _, _, _ := img.At(5, 5).RGBA(); r>>8 != 255 {
+		t.Error("MaskRegions() should not modify the original image")
+	}
+}
+
+func TestExtractRegion(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2560, 1440))
+
+	label, err := ExtractRegion(img, PresetRegions["room_label"])
+	if err != nil {
+		t.Fatalf("ExtractRegion() failed: %v", err)
+	}
+	expected := PresetRegions["room_label"].Rect(img.Bounds())
+	if label.Bounds().Dx() != expected.Dx() || label.Bounds().Dy() != expected.Dy() {
+		t.Errorf("Extracted size = %v, expected %v", label.Bounds(), expected)
+	}
+
+	if _, err := ExtractRegion(img, Region{X: 0.9, Y: 0, Width: 0.5, Height: 0.5}); err == nil {
+		t.Error("ExtractRegion() should reject regions outside the image")
+	}
+}
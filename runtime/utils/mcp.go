@@ -1,6 +1,9 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 // ExtractStringParam extracts and validates a string parameter from MCP arguments
 func ExtractStringParam(params map[string]any, paramName string) (string, error) {
@@ -20,3 +23,44 @@ func ExtractStringParam(params map[string]any, paramName string) (string, error)
 
 	return paramValue, nil
 }
+
+// TemplateArg returns a single valued template variable. mcp-go passes each matched variable as a list of values.
+func TemplateArg(args map[string]any, name string) (string, error) {
+	switch v := args[name].(type) {
+	case string:
+		return v, nil
+	case []string:
+		if len(v) == 1 {
+			return v[0], nil
+		}
+		if len(v) == 0 {
+			return "", nil
+		}
+	case nil:
+		return "", nil
+	}
+	return "", fmt.Errorf("'%s' must be a single value", name)
+}
+
+// TemplateIntArg parses an optional integer template variable within [minValue, maxValue]
+func TemplateIntArg(args map[string]any, name string, defaultValue, minValue, maxValue int) (int, error) {
+	raw, err := TemplateArg(args, name)
+	if err != nil || raw == "" {
+		return defaultValue, err
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < minValue || value > maxValue {
+		return 0, fmt.Errorf("'%s' must be an integer between %d and %d, got '%s'", name, minValue, maxValue, raw)
+	}
+	return value, nil
+}
+
+// HasTemplateArg reports whether any of the named template variables were given
+func HasTemplateArg(args map[string]any, names ...string) bool {
+	for _, name := range names {
+		if raw, _ := TemplateArg(args, name); raw != "" {
+			return true
+		}
+	}
+	return false
+}
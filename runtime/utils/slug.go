@@ -0,0 +1,20 @@
+package utils
+
+import "strings"
+
+// Slugify turns a name as the player types it into the form used in note paths and tags, e.g. "Mt. Holly" -> "mt_holly"
+func Slugify(name string) string {
+	var sb strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			underscore = false
+		case !underscore && sb.Len() > 0:
+			sb.WriteRune('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "_")
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Nook":              "nook",
+		"Mt. Holly":         "mt_holly",
+		"  Simon P. Jones ": "simon_p_jones",
+		"coat_of_arms":      "coat_of_arms",
+		"Room 46!":          "room_46",
+		"--":                "",
+	}
+	for name, expected := range tests {
+		if got := Slugify(name); got != expected {
+			t.Errorf("Slugify(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
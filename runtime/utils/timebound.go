@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the local date format accepted wherever a tool or prompt takes a date, e.g. "2025-05-04"
+const DateLayout = "2006-01-02"
+
+// ParseTimeBound parses a date or RFC3339 timestamp bounding a time range. An empty value is the zero time (unbounded).
+// Dates are read in local time, and a date used as the end of a range covers that whole day.
+func ParseTimeBound(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(DateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' must be a date (YYYY-MM-DD) or an RFC3339 timestamp", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
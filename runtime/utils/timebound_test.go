@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	day := time.Date(2025, 5, 4, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		value    string
+		end      bool
+		expected time.Time
+		wantErr  bool
+	}{
+		{name: "empty is unbounded", value: "", expected: time.Time{}},
+		{name: "date as start", value: "2025-05-04", expected: day},
+		{name: "date as end covers the whole day", value: "2025-05-04", end: true, expected: day.AddDate(0, 0, 1)},
+		{name: "timestamp", value: "2025-05-04T10:30:00Z", expected: time.Date(2025, 5, 4, 10, 30, 0, 0, time.UTC)},
+		{name: "timestamp as end is exact", value: "2025-05-04T10:30:00Z", end: true, expected: time.Date(2025, 5, 4, 10, 30, 0, 0, time.UTC)},
+		{name: "invalid", value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimeBound(tt.value, tt.end)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTimeBound(%q) expected an error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeBound(%q) returned error: %v", tt.value, err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParseTimeBound(%q, %v) = %v, expected %v", tt.value, tt.end, got, tt.expected)
+			}
+		})
+	}
+}
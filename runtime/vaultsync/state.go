@@ -0,0 +1,78 @@
+package vaultsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// State is the common ancestor of the local and remote trees: what every note looked like the last time both sides agreed.
+// It is device specific and lives in the vault's meta dir.
+type State struct {
+	LastSync string `json:"last_sync,omitempty"`
+	// Files maps a slash separated path relative to the notes dir to its state at the last sync
+	Files map[string]FileState `json:"files"`
+	path  string
+}
+
+// FileState records a synced note
+type FileState struct {
+	MD5       string `json:"md5"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// StatePath returns the location of the sync state within the vault
+func StatePath(vaultPath string) string {
+	return filepath.Join(vaultPath, vault.META_DIR, vault.SYNC_STATE)
+}
+
+// LoadState reads the sync state from the vault's meta dir.
+// A missing state is not an error; it means this device has never synced.
+func LoadState(vaultPath string) (*State, error) {
+	s := &State{
+		Files: make(map[string]FileState),
+		path:  StatePath(vaultPath),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state: %w", err)
+	}
+	if s.Files == nil {
+		s.Files = make(map[string]FileState)
+	}
+	return s, nil
+}
+
+// Save writes the sync state back to the vault's meta dir
+func (s *State) Save() error {
+	if err := utils.EnsureDirExists(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync state: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated state behind
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace sync state: %w", err)
+	}
+	return nil
+}
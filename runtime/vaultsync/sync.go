@@ -0,0 +1,346 @@
+// Package vaultsync mirrors the vault's notes tree to a folder in a storage backend so several devices can share one vault.
+//
+// Each device keeps the MD5 of every note as of its last sync in meta/sync_state.json. A note that only changed on one side
+// since then is copied to the other; a note that changed on both sides is a conflict. The version with the newer
+// updated_at frontmatter keeps the original path and the other is kept next to it as a conflicted copy, the same way
+// Obsidian names them, so no edit is ever lost.
+package vaultsync
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/storage"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/utils"
+)
+
+// REMOTE_DIR is the folder, relative to the store's root, that holds the shared notes tree
+const REMOTE_DIR = "vault_sync"
+
+// syncMu serializes syncs so two tool calls never interleave writes to the same state
+var syncMu sync.Mutex
+
+// Action is what a sync did to a single note
+type Action string
+
+const (
+	Pushed        Action = "pushed"
+	Pulled        Action = "pulled"
+	DeletedLocal  Action = "deleted_local"
+	DeletedRemote Action = "deleted_remote"
+	Conflicted    Action = "conflict"
+)
+
+// Change describes one note that differed between the vault and the remote tree
+type Change struct {
+	Path   string `json:"path"`
+	Action Action `json:"action"`
+	// ConflictCopy is the path the losing version of a conflict was saved under
+	ConflictCopy string `json:"conflict_copy,omitempty"`
+}
+
+// Result summarizes a sync
+type Result struct {
+	Changes   []Change `json:"changes"`
+	Unchanged int      `json:"unchanged"`
+	DryRun    bool     `json:"dry_run"`
+}
+
+// Syncer syncs the notes dir of the vault at VaultPath with RemoteDir in Store
+type Syncer struct {
+	Store     storage.Store
+	VaultPath string
+	RemoteDir string
+	// Device names this machine in conflicted copies
+	Device string
+	now    func() time.Time
+}
+
+func New(store storage.Store, vaultPath string) *Syncer {
+	device, err := os.Hostname()
+	if err != nil || device == "" {
+		device = "unknown device"
+	}
+	return &Syncer{
+		Store:     store,
+		VaultPath: vaultPath,
+		RemoteDir: REMOTE_DIR,
+		Device:    device,
+		now:       time.Now,
+	}
+}
+
+// side is one version of a note
+type side struct {
+	md5  string
+	data []byte
+}
+
+// Sync brings the vault's notes dir and the remote tree in line with each other.
+// With dryRun set, nothing is written and the result lists what would have changed.
+func (s *Syncer) Sync(dryRun bool) (*Result, error) {
+	syncMu.Lock()
+	defer syncMu.Unlock()
+
+	state, err := LoadState(s.VaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := s.localTree()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := s.Store.ListTree(s.RemoteDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote notes: %w", err)
+	}
+
+	paths := make(map[string]bool)
+	for p := range local {
+		paths[p] = true
+	}
+	for p := range remote {
+		if !isHidden(p) {
+			paths[p] = true
+		}
+	}
+	for p := range state.Files {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	result := &Result{Changes: []Change{}, DryRun: dryRun}
+	for _, p := range sorted {
+		l := local[p]
+		r := side{md5: remote[p].MD5}
+		base := state.Files[p].MD5
+
+		if l.md5 == r.md5 {
+			// Both sides agree (or the note is gone from both)
+			if l.md5 == "" {
+				delete(state.Files, p)
+			} else {
+				state.Files[p] = FileState{MD5: l.md5, UpdatedAt: updatedAt(l.data)}
+			}
+			result.Unchanged++
+			continue
+		}
+
+		change, err := s.resolve(p, l, r, base, local, remote, state, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = append(result.Changes, *change)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	state.LastSync = s.now().Format(time.RFC3339)
+	if err := state.Save(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// resolve decides what to do with a note whose local and remote versions differ, and does it unless dryRun is set
+func (s *Syncer) resolve(p string, l, r side, base string, local map[string]side, remote map[string]storage.RemoteFile, state *State, dryRun bool) (*Change, error) {
+	switch {
+	// Only the remote changed since the last sync
+	case l.md5 == base && r.md5 == "":
+		if !dryRun {
+			if err := s.deleteLocal(p); err != nil {
+				return nil, err
+			}
+			delete(state.Files, p)
+		}
+		return &Change{Path: p, Action: DeletedLocal}, nil
+
+	// Only the local copy changed since the last sync
+	case r.md5 == base && l.md5 == "":
+		if !dryRun {
+			if err := s.Store.DeleteFile(s.RemoteDir, p); err != nil {
+				return nil, fmt.Errorf("failed to delete remote note '%s': %w", p, err)
+			}
+			delete(state.Files, p)
+		}
+		return &Change{Path: p, Action: DeletedRemote}, nil
+
+	// Pull when only the remote changed. When one side was edited and the other deleted, the edit wins.
+	case l.md5 == base || l.md5 == "":
+		if !dryRun {
+			data, err := s.pull(p)
+			if err != nil {
+				return nil, err
+			}
+			state.Files[p] = FileState{MD5: r.md5, UpdatedAt: updatedAt(data)}
+		}
+		return &Change{Path: p, Action: Pulled}, nil
+
+	case r.md5 == base || r.md5 == "":
+		if !dryRun {
+			if err := s.Store.WriteFile(s.RemoteDir, p, l.data); err != nil {
+				return nil, fmt.Errorf("failed to push note '%s': %w", p, err)
+			}
+			state.Files[p] = FileState{MD5: l.md5, UpdatedAt: updatedAt(l.data)}
+		}
+		return &Change{Path: p, Action: Pushed}, nil
+	}
+
+	// Both sides changed: keep the newer note at p and the other as a conflicted copy
+	remoteData, err := s.Store.ReadFile(s.RemoteDir, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote note '%s': %w", p, err)
+	}
+	winner, loser := l.data, remoteData
+	if newer(remoteData, l.data) {
+		winner, loser = remoteData, l.data
+	}
+
+	copyPath := s.conflictPath(p, local, remote)
+	if !dryRun {
+		for _, write := range []struct {
+			path string
+			data []byte
+		}{{p, winner}, {copyPath, loser}} {
+			if err := s.writeLocal(write.path, write.data); err != nil {
+				return nil, err
+			}
+			if err := s.Store.WriteFile(s.RemoteDir, write.path, write.data); err != nil {
+				return nil, fmt.Errorf("failed to push note '%s': %w", write.path, err)
+			}
+			state.Files[write.path] = FileState{MD5: utils.MD5Bytes(write.data), UpdatedAt: updatedAt(write.data)}
+		}
+	}
+	return &Change{Path: p, Action: Conflicted, ConflictCopy: copyPath}, nil
+}
+
+// localTree reads every note in the vault's notes dir, keyed by slash separated path
+func (s *Syncer) localTree() (map[string]side, error) {
+	tree := make(map[string]side)
+
+	notesDir := filepath.Join(s.VaultPath, vault.NOTES_DIR)
+	if _, err := os.Stat(notesDir); os.IsNotExist(err) {
+		return tree, nil
+	}
+
+	files, err := utils.ListFiles(notesDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(notesDir, rel))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read note '%s': %w", rel, err)
+		}
+		tree[filepath.ToSlash(rel)] = side{md5: utils.MD5Bytes(data), data: data}
+	}
+	return tree, nil
+}
+
+// pull copies the remote version of p into the vault
+func (s *Syncer) pull(p string) ([]byte, error) {
+	data, err := s.Store.ReadFile(s.RemoteDir, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull note '%s': %w", p, err)
+	}
+	return data, s.writeLocal(p, data)
+}
+
+func (s *Syncer) writeLocal(p string, data []byte) error {
+	fullPath, err := s.notePath(p)
+	if err != nil {
+		return err
+	}
+	if err := utils.EnsureDirExists(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write note '%s': %w", p, err)
+	}
+	return nil
+}
+
+func (s *Syncer) deleteLocal(p string) error {
+	fullPath, err := s.notePath(p)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete note '%s': %w", p, err)
+	}
+	return nil
+}
+
+// notePath resolves a remote path inside the notes dir, rejecting paths that would escape it
+func (s *Syncer) notePath(p string) (string, error) {
+	cleanPath, err := utils.ValidatePath(filepath.FromSlash(p))
+	if err != nil {
+		return "", err
+	}
+	return utils.BuildSecurePath(s.VaultPath, vault.NOTES_DIR, cleanPath)
+}
+
+// conflictPath names the conflicted copy of p, e.g. "rooms/parlor (Conflicted copy laptop 202610181504).md"
+func (s *Syncer) conflictPath(p string, local map[string]side, remote map[string]storage.RemoteFile) string {
+	ext := path.Ext(p)
+	stem := strings.TrimSuffix(p, ext)
+	name := fmt.Sprintf("%s (Conflicted copy %s %s)", stem, s.Device, s.now().Format("200601021504"))
+
+	candidate := name + ext
+	for i := 2; ; i++ {
+		_, inLocal := local[candidate]
+		_, inRemote := remote[candidate]
+		if !inLocal && !inRemote {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s %d%s", name, i, ext)
+	}
+}
+
+// updatedAt returns the updated_at frontmatter of a note, or "" if it has none
+func updatedAt(data []byte) string {
+	metadata, _, err := notes.ParseContent(string(data))
+	if err != nil {
+		return ""
+	}
+	return metadata.UpdatedAt
+}
+
+// newer reports whether note a has a later updated_at than note b.
+// Notes without a parseable updated_at are never newer, so the local version wins ties.
+func newer(a, b []byte) bool {
+	ta, errA := time.Parse(time.RFC3339, updatedAt(a))
+	if errA != nil {
+		return false
+	}
+	tb, errB := time.Parse(time.RFC3339, updatedAt(b))
+	if errB != nil {
+		return true
+	}
+	return ta.After(tb)
+}
+
+// isHidden reports whether any segment of p starts with ".", matching what utils.ListFiles skips locally
+func isHidden(p string) bool {
+	for _, segment := range strings.Split(p, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package vaultsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/myungbeans/blueprince-mcp/runtime/models/notes"
+	"github.com/myungbeans/blueprince-mcp/runtime/models/vault"
+	"github.com/myungbeans/blueprince-mcp/runtime/storage/local"
+)
+
+var syncTime = time.Date(2026, 10, 18, 15, 4, 0, 0, time.UTC)
+
+// newDevice returns a Syncer for a fresh vault that shares root with every other device created from it
+func newDevice(t *testing.T, root, name string) *Syncer {
+	t.Helper()
+
+	vaultPath := t.TempDir()
+	s := New(local.NewStore(root, vaultPath), vaultPath)
+	s.Device = name
+	s.now = func() time.Time { return syncTime }
+	return s
+}
+
+func noteContent(t *testing.T, updatedAt, body string) string {
+	t.Helper()
+
+	content, err := notes.CreateContent(&notes.Metadata{Title: "Parlor", Category: "rooms", UpdatedAt: updatedAt}, body)
+	if err != nil {
+		t.Fatalf("Failed to create note content: %v", err)
+	}
+	return content
+}
+
+func writeNote(t *testing.T, s *Syncer, relPath, content string) {
+	t.Helper()
+
+	fullPath := filepath.Join(s.VaultPath, vault.NOTES_DIR, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Fatalf("Failed to create note dir: %v", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write note: %v", err)
+	}
+}
+
+func readNote(t *testing.T, s *Syncer, relPath string) (string, bool) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(s.VaultPath, vault.NOTES_DIR, filepath.FromSlash(relPath)))
+	if os.IsNotExist(err) {
+		return "", false
+	}
+	if err != nil {
+		t.Fatalf("Failed to read note: %v", err)
+	}
+	return string(data), true
+}
+
+func mustSync(t *testing.T, s *Syncer) *Result {
+	t.Helper()
+
+	result, err := s.Sync(false)
+	if err != nil {
+		t.Fatalf("Sync() on %s failed: %v", s.Device, err)
+	}
+	return result
+}
+
+func expectChanges(t *testing.T, result *Result, expected ...Change) {
+	t.Helper()
+
+	if len(result.Changes) != len(expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, result.Changes)
+	}
+	for i, change := range expected {
+		if result.Changes[i] != change {
+			t.Errorf("Change %d = %+v, expected %+v", i, result.Changes[i], change)
+		}
+	}
+}
+
+func TestSync_PushAndPull(t *testing.T) {
+	root := t.TempDir()
+	desktop := newDevice(t, root, "desktop")
+	laptop := newDevice(t, root, "laptop")
+
+	parlor := noteContent(t, "2026-10-18T10:00:00Z", "Three boxes")
+	writeNote(t, desktop, "rooms/parlor.md", parlor)
+	writeNote(t, desktop, ".obsidian/workspace.json", "{}")
+
+	expectChanges(t, mustSync(t, desktop), Change{Path: "rooms/parlor.md", Action: Pushed})
+	expectChanges(t, mustSync(t, laptop), Change{Path: "rooms/parlor.md", Action: Pulled})
+
+	if got, _ := readNote(t, laptop, "rooms/parlor.md"); got != parlor {
+		t.Errorf("Pulled note = %q, expected %q", got, parlor)
+	}
+	if _, ok := readNote(t, laptop, ".obsidian/workspace.json"); ok {
+		t.Error("Hidden files should not be synced")
+	}
+
+	// Nothing left to do on either side
+	for _, s := range []*Syncer{desktop, laptop} {
+		result := mustSync(t, s)
+		expectChanges(t, result)
+		if result.Unchanged != 1 {
+			t.Errorf("Expected 1 unchanged note on %s, got %d", s.Device, result.Unchanged)
+		}
+	}
+
+	state, err := LoadState(laptop.VaultPath)
+	if err != nil {
+		t.Fatalf("LoadState() failed: %v", err)
+	}
+	if state.Files["rooms/parlor.md"].UpdatedAt != "2026-10-18T10:00:00Z" {
+		t.Errorf("Sync state should record updated_at, got: %+v", state.Files)
+	}
+}
+
+func TestSync_Deletes(t *testing.T) {
+	root := t.TempDir()
+	desktop := newDevice(t, root, "desktop")
+	laptop := newDevice(t, root, "laptop")
+
+	writeNote(t, desktop, "rooms/parlor.md", noteContent(t, "", "Parlor"))
+	writeNote(t, desktop, "rooms/study.md", noteContent(t, "", "Study"))
+	mustSync(t, desktop)
+	mustSync(t, laptop)
+
+	// A local delete removes the remote copy...
+	os.Remove(filepath.Join(desktop.VaultPath, vault.NOTES_DIR, "rooms", "parlor.md"))
+	expectChanges(t, mustSync(t, desktop), Change{Path: "rooms/parlor.md", Action: DeletedRemote})
+
+	// ...which then removes it from other devices
+	expectChanges(t, mustSync(t, laptop), Change{Path: "rooms/parlor.md", Action: DeletedLocal})
+	if _, ok := readNote(t, laptop, "rooms/parlor.md"); ok {
+		t.Error("Note deleted on another device should be removed locally")
+	}
+	if _, ok := readNote(t, laptop, "rooms/study.md"); !ok {
+		t.Error("Untouched notes should be kept")
+	}
+}
+
+func TestSync_EditWinsOverDelete(t *testing.T) {
+	root := t.TempDir()
+	desktop := newDevice(t, root, "desktop")
+	laptop := newDevice(t, root, "laptop")
+
+	writeNote(t, desktop, "rooms/parlor.md", noteContent(t, "", "Parlor"))
+	mustSync(t, desktop)
+	mustSync(t, laptop)
+
+	edited := noteContent(t, "", "Parlor, with a new clue")
+	writeNote(t, desktop, "rooms/parlor.md", edited)
+	mustSync(t, desktop)
+
+	os.Remove(filepath.Join(laptop.VaultPath, vault.NOTES_DIR, "rooms", "parlor.md"))
+	expectChanges(t, mustSync(t, laptop), Change{Path: "rooms/parlor.md", Action: Pulled})
+	if got, _ := readNote(t, laptop, "rooms/parlor.md"); got != edited {
+		t.Errorf("Edited note should be restored, got %q", got)
+	}
+}
+
+func TestSync_Conflict(t *testing.T) {
+	root := t.TempDir()
+	desktop := newDevice(t, root, "desktop")
+	laptop := newDevice(t, root, "laptop")
+
+	writeNote(t, desktop, "rooms/parlor.md", noteContent(t, "2026-10-18T10:00:00Z", "Parlor"))
+	mustSync(t, desktop)
+	mustSync(t, laptop)
+
+	// Both devices edit the note before syncing; the laptop's edit is newer
+	desktopEdit := noteContent(t, "2026-10-18T11:00:00Z", "Parlor, desktop edit")
+	laptopEdit := noteContent(t, "2026-10-18T12:00:00Z", "Parlor, laptop edit")
+	writeNote(t, desktop, "rooms/parlor.md", desktopEdit)
+	writeNote(t, laptop, "rooms/parlor.md", laptopEdit)
+
+	expectChanges(t, mustSync(t, laptop), Change{Path: "rooms/parlor.md", Action: Pushed})
+
+	copyPath := "rooms/parlor (Conflicted copy desktop 202610181504).md"
+	expectChanges(t, mustSync(t, desktop), Change{Path: "rooms/parlor.md", Action: Conflicted, ConflictCopy: copyPath})
+
+	if got, _ := readNote(t, desktop, "rooms/parlor.md"); got != laptopEdit {
+		t.Errorf("Newer edit should keep the original path, got %q", got)
+	}
+	if got, _ := readNote(t, desktop, copyPath); got != desktopEdit {
+		t.Errorf("Older edit should be kept as a conflicted copy, got %q", got)
+	}
+
+	// The conflicted copy reaches the other device like any other note
+	expectChanges(t, mustSync(t, laptop), Change{Path: copyPath, Action: Pulled})
+	if got, _ := readNote(t, laptop, "rooms/parlor.md"); got != laptopEdit {
+		t.Errorf("Winning edit should be unchanged on the laptop, got %q", got)
+	}
+}
+
+func TestSync_ConflictWithoutUpdatedAt(t *testing.T) {
+	root := t.TempDir()
+	desktop := newDevice(t, root, "desktop")
+	laptop := newDevice(t, root, "laptop")
+
+	// Neither device has synced before, so there is no common ancestor
+	writeNote(t, desktop, "general/todo.md", "desktop list")
+	writeNote(t, laptop, "general/todo.md", "laptop list")
+	mustSync(t, desktop)
+
+	result := mustSync(t, laptop)
+	if len(result.Changes) != 1 || result.Changes[0].Action != Conflicted {
+		t.Fatalf("Expected a conflict, got %v", result.Changes)
+	}
+
+	// Without updated_at the local version wins
+	if got, _ := readNote(t, laptop, "general/todo.md"); got != "laptop list" {
+		t.Errorf("Local version should win a tie, got %q", got)
+	}
+	if got, _ := readNote(t, laptop, result.Changes[0].ConflictCopy); got != "desktop list" {
+		t.Errorf("Remote version should be the conflicted copy, got %q", got)
+	}
+}
+
+func TestSync_DryRun(t *testing.T) {
+	root := t.TempDir()
+	desktop := newDevice(t, root, "desktop")
+
+	writeNote(t, desktop, "rooms/parlor.md", noteContent(t, "", "Parlor"))
+
+	result, err := desktop.Sync(true)
+	if err != nil {
+		t.Fatalf("Sync() dry run failed: %v", err)
+	}
+	if !result.DryRun {
+		t.Error("Result should be marked as a dry run")
+	}
+	expectChanges(t, result, Change{Path: "rooms/parlor.md", Action: Pushed})
+
+	tree, err := desktop.Store.ListTree(REMOTE_DIR)
+	if err != nil {
+		t.Fatalf("ListTree() failed: %v", err)
+	}
+	if len(tree) != 0 {
+		t.Errorf("Dry run should not push anything, got: %v", tree)
+	}
+	if _, err := os.Stat(StatePath(desktop.VaultPath)); !os.IsNotExist(err) {
+		t.Error("Dry run should not save sync state")
+	}
+}